@@ -0,0 +1,80 @@
+package porter
+
+import (
+	"unicode/utf8"
+)
+
+// maxASCIIWord is the length of the longest ASCII word that StemBytes and
+// AppendStem stem without allocating.  Longer words take the []rune path.
+const maxASCIIWord = 64
+
+// lowerASCII copies the bytes of an ASCII word into buf, converting them to
+// lower case.  It returns false if the word is not ASCII, or is too long.
+func lowerASCII(buf []rune, b []byte) ([]rune, bool) {
+	if len(b) > cap(buf) {
+		return nil, false
+	}
+	for _, c := range b {
+		if c >= utf8.RuneSelf {
+			return nil, false
+		}
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		buf = append(buf, rune(c))
+	}
+	return buf, true
+}
+
+// lowerASCIIString is lowerASCII for a string.
+func lowerASCIIString(buf []rune, s string) ([]rune, bool) {
+	if len(s) > cap(buf) {
+		return nil, false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= utf8.RuneSelf {
+			return nil, false
+		}
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		buf = append(buf, rune(c))
+	}
+	return buf, true
+}
+
+// appendASCII appends ASCII runes to dst as bytes.
+func appendASCII(dst []byte, s []rune) []byte {
+	for _, r := range s {
+		dst = append(dst, byte(r))
+	}
+	return dst
+}
+
+// StemBytes lower cases and stems a UTF-8 encoded word.  ASCII words are
+// stemmed without allocating.
+//
+// Like Stem, StemBytes may modify b, and the result may share its memory.
+// Lower casing can make a word longer in UTF-8, and a stem that does not fit
+// in b is returned in new memory, so the bytes after b are never written.
+func StemBytes(b []byte) []byte {
+	var buf [maxASCIIWord]rune
+	if s, ok := lowerASCII(buf[:0], b); ok {
+		s = StemWithoutLowerCasing(s)
+		return appendASCII(b[:0:len(b)], s)
+	}
+	s := Stem([]rune(string(b)))
+	return append(b[:0:len(b)], string(s)...)
+}
+
+// AppendStem appends the stem of word to dst, and returns the extended buffer.
+// ASCII words are stemmed without allocating, other than to grow dst.
+func AppendStem(dst []byte, word string) []byte {
+	var buf [maxASCIIWord]rune
+	if s, ok := lowerASCIIString(buf[:0], word); ok {
+		s = StemWithoutLowerCasing(s)
+		return appendASCII(dst, s)
+	}
+	return append(dst, StemString(word)...)
+}
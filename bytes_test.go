@@ -0,0 +1,98 @@
+package porter
+
+import (
+	"testing"
+)
+
+func TestStemBytes(t *testing.T) {
+	tests := []string{
+		"",
+		"a",
+		"Waxes",
+		"GENERALIZATIONS",
+		"controlling",
+		"naïveté",
+		"Ünïcödé",
+		"ȺȺȺations",
+	}
+	for _, vs := range [][]string{tests, getVoc()} {
+		for _, s := range vs {
+			exp := StemString(s)
+			if stem := string(StemBytes([]byte(s))); stem != exp {
+				t.Errorf("StemBytes(%q) = %q, expected %q", s, stem, exp)
+			}
+			if stem := string(AppendStem(nil, s)); stem != exp {
+				t.Errorf("AppendStem(nil, %q) = %q, expected %q", s, stem, exp)
+			}
+			if stem := string(AppendStem([]byte("x "), s)); stem != "x "+exp {
+				t.Errorf("AppendStem(\"x \", %q) = %q, expected %q", s, stem, "x "+exp)
+			}
+		}
+	}
+}
+
+func TestStemBytesSubslice(t *testing.T) {
+	// Lower casing makes each Ⱥ one byte longer, so the stem is longer
+	// than the word.
+	for _, s := range []string{"ȺȺȺations", "naïveté", "controlling"} {
+		buf := []byte(s + "|NEXT")
+		n := len(s)
+		exp := StemString(s)
+		if stem := string(StemBytes(buf[:n])); stem != exp {
+			t.Errorf("StemBytes(%q) = %q, expected %q", s, stem, exp)
+		}
+		if tail := string(buf[n:]); tail != "|NEXT" {
+			t.Errorf("StemBytes(%q) overwrote the bytes after it: %q", s, tail)
+		}
+	}
+}
+
+func TestStemBytesAllocs(t *testing.T) {
+	b := make([]byte, 0, maxASCIIWord)
+	dst := make([]byte, 0, maxASCIIWord)
+	for _, s := range []string{"Generalizations", "controlling", "ponies"} {
+		allocs := testing.AllocsPerRun(100, func() {
+			b = append(b[:0], s...)
+			_ = StemBytes(b)
+		})
+		if allocs != 0 {
+			t.Errorf("StemBytes(%q) made %v allocations, expected none", s, allocs)
+		}
+		allocs = testing.AllocsPerRun(100, func() {
+			dst = AppendStem(dst[:0], s)
+		})
+		if allocs != 0 {
+			t.Errorf("AppendStem(dst, %q) made %v allocations, expected none", s, allocs)
+		}
+	}
+}
+
+func BenchmarkBytes(b *testing.B) {
+	ss := getVoc()
+	bs := make([][]byte, len(ss))
+	for i, s := range ss {
+		bs[i] = []byte(s)
+	}
+	buf := make([]byte, 0, maxASCIIWord)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, s := range bs {
+			buf = append(buf[:0], s...)
+			stem := StemBytes(buf)
+			_ = stem
+		}
+	}
+}
+
+func BenchmarkAppendStem(b *testing.B) {
+	ss := getVoc()
+	buf := make([]byte, 0, maxASCIIWord)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, s := range ss {
+			buf = AppendStem(buf[:0], s)
+		}
+	}
+}
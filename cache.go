@@ -0,0 +1,132 @@
+package porter
+
+import (
+	"container/list"
+	"sync"
+)
+
+// DefaultCacheEntries is the number of entries a CachedStemmer holds if
+// neither limit is set in its CacheOptions.
+const DefaultCacheEntries = 1 << 16
+
+// cacheEntryOverhead is roughly how many bytes a cache entry uses, besides
+// the bytes of the word and its stem.
+const cacheEntryOverhead = 128
+
+// CacheOptions configures a CachedStemmer.  A limit of zero means no limit.
+type CacheOptions struct {
+	// MaxEntries is the most words the cache holds.
+	MaxEntries int
+
+	// MaxBytes is roughly the most memory the cache uses, counting the bytes
+	// of each word and its stem, plus a fixed overhead per entry.
+	MaxBytes int
+}
+
+// CacheStats are the counters of a CachedStemmer.
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Entries   int
+	Bytes     int
+}
+
+// cacheEntry is a word and its stem.
+type cacheEntry struct {
+	word, stem string
+}
+
+// size returns roughly how many bytes the entry uses.
+func (e *cacheEntry) size() int {
+	return len(e.word) + len(e.stem) + cacheEntryOverhead
+}
+
+// CachedStemmer remembers the stems of the words it has seen most recently,
+// so that stemming them again is only a lookup.  It is safe for concurrent
+// use.
+type CachedStemmer struct {
+	s    Stemmer
+	opts CacheOptions
+
+	mu    sync.Mutex
+	lru   *list.List // of *cacheEntry, most recently used first
+	words map[string]*list.Element
+	stats CacheStats
+}
+
+// NewCachedStemmer returns a CachedStemmer that stems with s, or with the C
+// reference rules if s is nil.
+func NewCachedStemmer(s Stemmer, opts CacheOptions) *CachedStemmer {
+	if s == nil {
+		s = defaultPorter
+	}
+	if opts.MaxEntries <= 0 && opts.MaxBytes <= 0 {
+		opts.MaxEntries = DefaultCacheEntries
+	}
+	return &CachedStemmer{
+		s:     s,
+		opts:  opts,
+		lru:   list.New(),
+		words: make(map[string]*list.Element),
+	}
+}
+
+// StemString returns the stem of a word, the same as the Stemmer it wraps.
+func (c *CachedStemmer) StemString(s string) string {
+	c.mu.Lock()
+	if e, ok := c.words[s]; ok {
+		c.lru.MoveToFront(e)
+		c.stats.Hits++
+		stem := e.Value.(*cacheEntry).stem
+		c.mu.Unlock()
+		return stem
+	}
+	c.stats.Misses++
+	c.mu.Unlock()
+
+	stem := c.s.StemString(s)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.words[s]; ok {
+		// Another goroutine stemmed the word while we did.
+		return stem
+	}
+	entry := &cacheEntry{word: s, stem: stem}
+	c.words[s] = c.lru.PushFront(entry)
+	c.stats.Entries++
+	c.stats.Bytes += entry.size()
+	c.evict()
+	return stem
+}
+
+// evict removes the least recently used entries until the cache is within
+// its limits.  It always keeps the most recent entry.
+func (c *CachedStemmer) evict() {
+	for c.stats.Entries > 1 && (c.opts.MaxEntries > 0 && c.stats.Entries > c.opts.MaxEntries ||
+		c.opts.MaxBytes > 0 && c.stats.Bytes > c.opts.MaxBytes) {
+		e := c.lru.Back()
+		entry := c.lru.Remove(e).(*cacheEntry)
+		delete(c.words, entry.word)
+		c.stats.Entries--
+		c.stats.Bytes -= entry.size()
+		c.stats.Evictions++
+	}
+}
+
+// Stats returns the current counters of the cache.
+func (c *CachedStemmer) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// Reset empties the cache and zeroes its counters.
+func (c *CachedStemmer) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lru.Init()
+	c.words = make(map[string]*list.Element)
+	c.stats = CacheStats{}
+}
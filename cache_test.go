@@ -0,0 +1,130 @@
+package porter
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func TestCachedStemmer(t *testing.T) {
+	c := NewCachedStemmer(nil, CacheOptions{})
+	voc := getVoc()
+	for i := 0; i < 2; i++ {
+		for _, word := range voc {
+			if stem, exp := c.StemString(word), StemString(word); stem != exp {
+				t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", word, stem, exp)
+			}
+		}
+	}
+	stats := c.Stats()
+	words := make(map[string]bool)
+	for _, word := range voc {
+		words[word] = true
+	}
+	if stats.Misses != uint64(len(words)) || stats.Hits != uint64(2*len(voc)-len(words)) || stats.Evictions != 0 {
+		t.Errorf("Stats() = %+v, expected %d misses, %d hits and no evictions", stats, len(words), 2*len(voc)-len(words))
+	}
+
+	strict := NewCachedStemmer(New(Options{Strict: true}), CacheOptions{})
+	if stem := strict.StemString("analogy"); stem != "analogi" {
+		t.Errorf("strict StemString(\"analogy\") = %q, expected \"analogi\"", stem)
+	}
+}
+
+func TestCachedStemmerMaxEntries(t *testing.T) {
+	c := NewCachedStemmer(nil, CacheOptions{MaxEntries: 2})
+	c.StemString("running")
+	c.StemString("jumping")
+	c.StemString("running") // hit, so jumping is now the least recently used
+	c.StemString("walking") // evicts jumping
+	c.StemString("running") // hit
+	c.StemString("jumping") // miss, evicts walking
+	exp := CacheStats{Hits: 2, Misses: 4, Evictions: 2, Entries: 2, Bytes: 2*cacheEntryOverhead + len("runningrunjumpingjump")}
+	if stats := c.Stats(); stats != exp {
+		t.Errorf("Stats() = %+v, expected %+v", stats, exp)
+	}
+
+	c.Reset()
+	if stats := c.Stats(); stats != (CacheStats{}) {
+		t.Errorf("Stats() after Reset() = %+v, expected zeroes", stats)
+	}
+}
+
+func TestCachedStemmerMaxBytes(t *testing.T) {
+	max := 3 * (cacheEntryOverhead + 16)
+	c := NewCachedStemmer(nil, CacheOptions{MaxBytes: max})
+	for _, word := range getVoc() {
+		c.StemString(word)
+		if stats := c.Stats(); stats.Bytes > max && stats.Entries > 1 {
+			t.Fatalf("cache uses %d bytes, more than its limit of %d", stats.Bytes, max)
+		}
+	}
+	if stats := c.Stats(); stats.Evictions == 0 {
+		t.Errorf("Stats() = %+v, expected some evictions", stats)
+	}
+}
+
+func TestCachedStemmerConcurrent(t *testing.T) {
+	c := NewCachedStemmer(nil, CacheOptions{MaxEntries: 100})
+	voc := getVoc()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			for _, i := range zipfIndexes(len(voc), 5000, seed) {
+				if stem, exp := c.StemString(voc[i]), StemString(voc[i]); stem != exp {
+					t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", voc[i], stem, exp)
+				}
+			}
+		}(int64(i))
+	}
+	wg.Wait()
+	if stats := c.Stats(); stats.Entries > 100 || stats.Hits+stats.Misses != 4*5000 {
+		t.Errorf("Stats() = %+v, expected at most 100 entries and %d lookups", stats, 4*5000)
+	}
+}
+
+// zipfIndexes returns n indexes into a vocabulary of size words, with the
+// Zipf distribution of word frequencies in real text.
+func zipfIndexes(size, n int, seed int64) []int {
+	z := rand.NewZipf(rand.New(rand.NewSource(seed)), 1.1, 1, uint64(size-1))
+	indexes := make([]int, n)
+	for i := range indexes {
+		indexes[i] = int(z.Uint64())
+	}
+	return indexes
+}
+
+// zipfVoc returns the vocabulary repeated as in real text.
+func zipfVoc() []string {
+	voc := getVoc()
+	ss := make([]string, len(voc))
+	for i, j := range zipfIndexes(len(voc), len(voc), 1) {
+		ss[i] = voc[j]
+	}
+	return ss
+}
+
+func BenchmarkStringZipf(b *testing.B) {
+	ss := zipfVoc()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, s := range ss {
+			stem := StemString(s)
+			_ = stem
+		}
+	}
+}
+
+func BenchmarkCachedStringZipf(b *testing.B) {
+	ss := zipfVoc()
+	c := NewCachedStemmer(nil, CacheOptions{MaxEntries: 4096})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, s := range ss {
+			stem := c.StemString(s)
+			_ = stem
+		}
+	}
+}
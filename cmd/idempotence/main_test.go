@@ -0,0 +1,26 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	porter "github.com/ksshannon/go-porterstemmer"
+)
+
+func TestReport(t *testing.T) {
+	words, err := readWords(strings.NewReader("abuse\r\n\n  ponies \naccidentally\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []string{"abuse", "ponies", "accidentally"}; strings.Join(words, ",") != strings.Join(exp, ",") {
+		t.Errorf("readWords = %q, expected %q", words, exp)
+	}
+	var out bytes.Buffer
+	if err := report(&out, porter.NonIdempotent(porter.New(porter.Options{}), words, 8)); err != nil {
+		t.Fatal(err)
+	}
+	if exp := "abuse\tabus -> abu\naccidentally\taccident -> accid\n"; out.String() != exp {
+		t.Errorf("report wrote %q, expected %q", out.String(), exp)
+	}
+}
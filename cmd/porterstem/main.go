@@ -0,0 +1,269 @@
+// Command porterstem stems words read from files or the standard input.
+//
+// By default it reads one word per line and writes one stem per line, like
+// the reference C program does for voc.txt and output.txt:
+//
+//	porterstem voc.txt > output.txt
+//
+// With -tokenize it splits free text into words instead.  The output can be
+// just the stems, the words and stems separated by a tab, or JSON lines.
+// Large inputs are stemmed in parallel, but the output is always in the same
+// order as the input.
+//
+// With -snowball it runs a stemmer written in Snowball, from a .sbl file:
+//
+//	porterstem -snowball english.sbl voc.txt
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+
+	porter "github.com/ksshannon/go-porterstemmer"
+	"github.com/ksshannon/go-porterstemmer/snowball"
+)
+
+// batchSize is how many lines are stemmed together by one worker.
+const batchSize = 4096
+
+// config is what the flags say to do.
+type config struct {
+	format   string
+	tokenize bool
+	noLower  bool
+	workers  int
+	stemmer  porter.Stemmer
+}
+
+// line is a line of input, and its byte offset in the input.
+type line struct {
+	text   string
+	offset int
+}
+
+// result is a stemmed word, and where it was in the input.
+type result struct {
+	Word  string `json:"word"`
+	Stem  string `json:"stem"`
+	Start *int   `json:"start,omitempty"`
+	End   *int   `json:"end,omitempty"`
+}
+
+// stemLine returns the results for a line of input.
+func (c *config) stemLine(l line) []result {
+	if !c.tokenize {
+		word := strings.TrimSpace(l.text)
+		var stem string
+		if c.noLower {
+			stem = string(c.stemmer.StemWithoutLowerCasing([]rune(word)))
+		} else {
+			stem = c.stemmer.StemString(word)
+		}
+		return []result{{Word: word, Stem: stem}}
+	}
+	var results []result
+	opts := porter.TokenizerOptions{Apostrophes: true, Unicode: true, Stemmer: c.stemmer}
+	if c.noLower {
+		opts.Stemmer = noLowerStemmer{c.stemmer}
+	}
+	for _, tok := range porter.Tokenize(l.text, opts) {
+		start, end := l.offset+tok.Start, l.offset+tok.End
+		results = append(results, result{Word: tok.Term, Stem: tok.Stem, Start: &start, End: &end})
+	}
+	return results
+}
+
+// noLowerStemmer is a stemmer that never lower cases the words it stems, for
+// the tokenizer to use with -nolower.
+type noLowerStemmer struct {
+	porter.Stemmer
+}
+
+func (s noLowerStemmer) StemString(word string) string {
+	return string(s.StemWithoutLowerCasing([]rune(word)))
+}
+
+func (s noLowerStemmer) Stem(word []rune) []rune {
+	return s.StemWithoutLowerCasing(word)
+}
+
+// stemmerName returns the name of the registered stemmer that the -algorithm
+// and -strict flags ask for, or an error if they ask for different ones.
+// algorithmSet is whether -algorithm was given at all.
+func stemmerName(algorithm string, algorithmSet, strict bool) (string, error) {
+	if !strict {
+		return algorithm, nil
+	}
+	if algorithmSet && algorithm != "porter" && algorithm != "porter-strict" {
+		return "", fmt.Errorf("-strict is for the porter algorithm, not %q", algorithm)
+	}
+	return "porter-strict", nil
+}
+
+// write writes results in the configured format.
+func (c *config) write(w *bufio.Writer, results []result) error {
+	for _, r := range results {
+		switch c.format {
+		case "stem":
+			w.WriteString(r.Stem)
+		case "tsv":
+			w.WriteString(r.Word)
+			w.WriteByte('\t')
+			w.WriteString(r.Stem)
+		case "json":
+			data, err := json.Marshal(r)
+			if err != nil {
+				return err
+			}
+			w.Write(data)
+		}
+		if err := w.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// process stems everything read from r, and writes it to w.  Batches of
+// lines are stemmed in parallel, and written in order.
+func (c *config) process(r io.Reader, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	pending := make(chan chan []result, c.workers)
+	readErr := make(chan error, 1)
+
+	go func() {
+		defer close(pending)
+		br := bufio.NewReader(r)
+		offset := 0
+		batch := make([]line, 0, batchSize)
+		flush := func() {
+			lines := batch
+			done := make(chan []result, 1)
+			pending <- done
+			go func() {
+				var results []result
+				for _, l := range lines {
+					results = append(results, c.stemLine(l)...)
+				}
+				done <- results
+			}()
+			batch = make([]line, 0, batchSize)
+		}
+		for {
+			text, err := br.ReadString('\n')
+			if len(text) > 0 {
+				batch = append(batch, line{text: strings.TrimRight(text, "\r\n"), offset: offset})
+				offset += len(text)
+				if len(batch) == batchSize {
+					flush()
+				}
+			}
+			if err != nil {
+				if len(batch) > 0 {
+					flush()
+				}
+				if err == io.EOF {
+					err = nil
+				}
+				readErr <- err
+				return
+			}
+		}
+	}()
+
+	var err error
+	for done := range pending {
+		if results := <-done; err == nil {
+			err = c.write(bw, results)
+		}
+	}
+	if rerr := <-readErr; err == nil {
+		err = rerr
+	}
+	if ferr := bw.Flush(); err == nil {
+		err = ferr
+	}
+	return err
+}
+
+func main() {
+	var c config
+	var algorithm string
+	var strict bool
+	var program string
+	flag.StringVar(&c.format, "format", "stem", "output format: stem, tsv (word<TAB>stem) or json (JSON lines)")
+	flag.BoolVar(&c.tokenize, "tokenize", false, "split free text into words, rather than reading one word per line")
+	flag.BoolVar(&c.noLower, "nolower", false, "do not lower case words before stemming them")
+	flag.StringVar(&algorithm, "algorithm", "porter", "the stemmer to use: "+strings.Join(porter.Names(), ", "))
+	flag.BoolVar(&strict, "strict", false, "follow the published algorithm, rather than the C reference (the same as -algorithm porter-strict, and an error with any other algorithm)")
+	flag.StringVar(&program, "snowball", "", "run the stemmer of a Snowball (.sbl) program, rather than a registered one")
+	flag.IntVar(&c.workers, "j", runtime.NumCPU(), "number of batches to stem in parallel")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s [flags] [file ...]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	switch c.format {
+	case "stem", "tsv", "json":
+	default:
+		fmt.Fprintf(os.Stderr, "%s: unknown format %q\n", os.Args[0], c.format)
+		flag.Usage()
+		os.Exit(2)
+	}
+	if c.workers < 1 {
+		c.workers = 1
+	}
+	set := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	if program != "" && (set["algorithm"] || strict) {
+		fmt.Fprintf(os.Stderr, "%s: -snowball cannot be used with -algorithm or -strict\n", os.Args[0])
+		flag.Usage()
+		os.Exit(2)
+	}
+	algorithm, err := stemmerName(algorithm, set["algorithm"], strict)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", os.Args[0], err)
+		flag.Usage()
+		os.Exit(2)
+	}
+	if program != "" {
+		if c.stemmer, err = snowball.Load(program); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", os.Args[0], err)
+			os.Exit(1)
+		}
+	} else if c.stemmer, err = porter.Lookup(algorithm); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", os.Args[0], err)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	files := flag.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	status := 0
+	for _, name := range files {
+		var err error
+		if name == "-" {
+			err = c.process(os.Stdin, os.Stdout)
+		} else {
+			var f *os.File
+			if f, err = os.Open(name); err == nil {
+				err = c.process(f, os.Stdout)
+				f.Close()
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", os.Args[0], err)
+			status = 1
+		}
+	}
+	os.Exit(status)
+}
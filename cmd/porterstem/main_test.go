@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	porter "github.com/ksshannon/go-porterstemmer"
+	"github.com/ksshannon/go-porterstemmer/snowball"
+)
+
+func TestProcess(t *testing.T) {
+	var words []string
+	for i := 0; i < 3*batchSize; i++ {
+		words = append(words, []string{"Caresses", "ponies", "relational", "", "hopping"}[i%5])
+	}
+	input := strings.Join(words, "\r\n") + "\n"
+
+	tests := []struct {
+		format  string
+		noLower bool
+		line    func(word string) string
+	}{
+		{"stem", false, func(word string) string { return porter.StemString(word) }},
+		{"tsv", false, func(word string) string { return word + "\t" + porter.StemString(word) }},
+		{"stem", true, func(word string) string { return string(porter.StemWithoutLowerCasing([]rune(word))) }},
+	}
+	for _, test := range tests {
+		c := config{format: test.format, noLower: test.noLower, workers: 4, stemmer: porter.New(porter.Options{})}
+		var out bytes.Buffer
+		if err := c.process(strings.NewReader(input), &out); err != nil {
+			t.Fatalf("%s", err)
+		}
+		lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+		if len(lines) != len(words) {
+			t.Fatalf("format %s wrote %d lines, expected %d", test.format, len(lines), len(words))
+		}
+		for i, word := range words {
+			if exp := test.line(word); lines[i] != exp {
+				t.Errorf("format %s line %d = %q, expected %q", test.format, i, lines[i], exp)
+				break
+			}
+		}
+	}
+}
+
+func TestProcessTokenizeJSON(t *testing.T) {
+	input := "The runners were running.\r\nHopping, they hoped.\n"
+	c := config{format: "json", tokenize: true, workers: 2, stemmer: porter.New(porter.Options{})}
+	var out bytes.Buffer
+	if err := c.process(strings.NewReader(input), &out); err != nil {
+		t.Fatalf("%s", err)
+	}
+	dec := json.NewDecoder(&out)
+	var stems []string
+	for dec.More() {
+		var r struct {
+			Word, Stem string
+			Start, End int
+		}
+		if err := dec.Decode(&r); err != nil {
+			t.Fatalf("%s", err)
+		}
+		if input[r.Start:r.End] != r.Word {
+			t.Errorf("word %q has offsets [%d:%d], which is %q", r.Word, r.Start, r.End, input[r.Start:r.End])
+		}
+		stems = append(stems, r.Stem)
+	}
+	if got, exp := strings.Join(stems, " "), "the runner were run hop thei hope"; got != exp {
+		t.Errorf("stems = %q, expected %q", got, exp)
+	}
+}
+
+func TestProcessSnowball(t *testing.T) {
+	p, err := snowball.Load("../../snowball/testdata/porter_c.sbl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	words := []string{"Caresses", "ponies", "relational", "hopping", "by"}
+	c := config{format: "tsv", workers: 2, stemmer: p}
+	var out bytes.Buffer
+	if err := c.process(strings.NewReader(strings.Join(words, "\n")), &out); err != nil {
+		t.Fatalf("%s", err)
+	}
+	var exp strings.Builder
+	for _, word := range words {
+		exp.WriteString(word + "\t" + porter.StemString(word) + "\n")
+	}
+	if out.String() != exp.String() {
+		t.Errorf("process wrote %q, expected %q", out.String(), exp.String())
+	}
+}
+
+// countingStemmer counts the words it stems.
+type countingStemmer struct {
+	porter.Stemmer
+	n int
+}
+
+func (s *countingStemmer) StemString(word string) string {
+	s.n++
+	return s.Stemmer.StemString(word)
+}
+
+func (s *countingStemmer) Stem(word []rune) []rune {
+	s.n++
+	return s.Stemmer.Stem(word)
+}
+
+func (s *countingStemmer) StemWithoutLowerCasing(word []rune) []rune {
+	s.n++
+	return s.Stemmer.StemWithoutLowerCasing(word)
+}
+
+func TestProcessTokenizeNoLower(t *testing.T) {
+	stemmer := &countingStemmer{Stemmer: porter.New(porter.Options{})}
+	c := config{format: "stem", tokenize: true, noLower: true, workers: 1, stemmer: stemmer}
+	var out bytes.Buffer
+	if err := c.process(strings.NewReader("Running runners\n"), &out); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if got, exp := out.String(), "Run\nrunner\n"; got != exp {
+		t.Errorf("process wrote %q, expected %q", got, exp)
+	}
+	if stemmer.n != 2 {
+		t.Errorf("stemmed %d times, expected 2, once for each word", stemmer.n)
+	}
+}
+
+func TestStemmerName(t *testing.T) {
+	tests := []struct {
+		algorithm    string
+		algorithmSet bool
+		strict       bool
+		exp          string
+		err          bool
+	}{
+		{"porter", false, false, "porter", false},
+		{"lancaster", true, false, "lancaster", false},
+		{"porter", false, true, "porter-strict", false},
+		{"porter", true, true, "porter-strict", false},
+		{"porter-strict", true, true, "porter-strict", false},
+		{"lancaster", true, true, "", true},
+		{"porter2", true, true, "", true},
+	}
+	for _, test := range tests {
+		name, err := stemmerName(test.algorithm, test.algorithmSet, test.strict)
+		if name != test.exp || (err != nil) != test.err {
+			t.Errorf("stemmerName(%q, %t, %t) = %q, %v, expected %q and an error: %t", test.algorithm, test.algorithmSet, test.strict, name, err, test.exp, test.err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package porter
+
+import (
+	"unicode"
+)
+
+// StemCopy is like Stem, but never modifies s.  The result does not share
+// memory with s.
+func StemCopy(s []rune) []rune {
+	return defaultPorter.StemCopy(s)
+}
+
+// StemInto is like StemCopy, but copies s into buf to stem it, and writes the
+// stem into buf.  buf is grown as by append if s or its stem does not fit, so
+// the result shares memory with buf whenever buf has room.
+func StemInto(buf, s []rune) []rune {
+	return defaultPorter.StemInto(buf, s)
+}
+
+// StemWithoutLowerCasingCopy is like StemWithoutLowerCasing, but never
+// modifies s.  The result does not share memory with s.
+func StemWithoutLowerCasingCopy(s []rune) []rune {
+	return defaultPorter.StemWithoutLowerCasingCopy(s)
+}
+
+// StemWithoutLowerCasingInto is like StemWithoutLowerCasingCopy, but copies s
+// into buf to stem it, and writes the stem into buf.  buf is grown as by append
+// if s or its stem does not fit, so the result shares memory with buf whenever
+// buf has room.
+func StemWithoutLowerCasingInto(buf, s []rune) []rune {
+	return defaultPorter.StemWithoutLowerCasingInto(buf, s)
+}
+
+// StemCopy is like Stem, but never modifies s.  The result does not share
+// memory with s.
+func (p *Porter) StemCopy(s []rune) []rune {
+	return p.StemInto(nil, s)
+}
+
+// StemInto is like StemCopy, but copies s into buf to stem it, and writes the
+// stem into buf.  buf is grown as by append if s or its stem does not fit, so
+// the result shares memory with buf whenever buf has room.
+func (p *Porter) StemInto(buf, s []rune) []rune {
+	buf = buf[:0]
+	for _, r := range s {
+		buf = append(buf, unicode.ToLower(r))
+	}
+	// An exception can map a word to a longer stem, which StemWithoutLowerCasing
+	// returns in new memory.
+	return append(buf[:0], p.StemWithoutLowerCasing(buf)...)
+}
+
+// StemWithoutLowerCasingCopy is like StemWithoutLowerCasing, but never
+// modifies s.  The result does not share memory with s.
+func (p *Porter) StemWithoutLowerCasingCopy(s []rune) []rune {
+	return p.StemWithoutLowerCasingInto(nil, s)
+}
+
+// StemWithoutLowerCasingInto is like StemWithoutLowerCasingCopy, but copies s
+// into buf to stem it, and writes the stem into buf.  buf is grown as by append
+// if s or its stem does not fit, so the result shares memory with buf whenever
+// buf has room.
+func (p *Porter) StemWithoutLowerCasingInto(buf, s []rune) []rune {
+	buf = append(buf[:0], s...)
+	return append(buf[:0], p.StemWithoutLowerCasing(buf)...)
+}
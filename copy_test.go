@@ -0,0 +1,101 @@
+package porter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStemCopy(t *testing.T) {
+	strict := New(Options{Strict: true})
+	var buf []rune
+	for _, word := range getVoc() {
+		for _, w := range []string{word, strings.ToUpper(word)} {
+			s := []rune(w)
+
+			if stem := string(StemCopy(s)); stem != StemString(w) {
+				t.Errorf("StemCopy(%q) = %q, expected %q", w, stem, StemString(w))
+			}
+			if string(s) != w {
+				t.Fatalf("StemCopy modified its input from %q to %q", w, string(s))
+			}
+
+			buf = StemInto(buf, s)
+			if stem := string(buf); stem != StemString(w) {
+				t.Errorf("StemInto(buf, %q) = %q, expected %q", w, stem, StemString(w))
+			}
+			if string(s) != w {
+				t.Fatalf("StemInto modified its input from %q to %q", w, string(s))
+			}
+
+			exp := string(StemWithoutLowerCasing([]rune(w)))
+			if stem := string(StemWithoutLowerCasingCopy(s)); stem != exp {
+				t.Errorf("StemWithoutLowerCasingCopy(%q) = %q, expected %q", w, stem, exp)
+			}
+			if string(s) != w {
+				t.Fatalf("StemWithoutLowerCasingCopy modified its input from %q to %q", w, string(s))
+			}
+
+			buf = StemWithoutLowerCasingInto(buf, s)
+			if stem := string(buf); stem != exp {
+				t.Errorf("StemWithoutLowerCasingInto(buf, %q) = %q, expected %q", w, stem, exp)
+			}
+			if string(s) != w {
+				t.Fatalf("StemWithoutLowerCasingInto modified its input from %q to %q", w, string(s))
+			}
+
+			if stem := string(strict.StemCopy(s)); stem != strict.StemString(w) {
+				t.Errorf("strict StemCopy(%q) = %q, expected %q", w, stem, strict.StemString(w))
+			}
+			if string(s) != w {
+				t.Fatalf("strict StemCopy modified its input from %q to %q", w, string(s))
+			}
+		}
+	}
+}
+
+func TestStemIntoAllocs(t *testing.T) {
+	s := []rune("Generalizations")
+	buf := make([]rune, 0, len(s))
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = StemInto(buf, s)
+	})
+	if allocs != 0 {
+		t.Errorf("StemInto made %v allocations, expected none", allocs)
+	}
+}
+
+func TestStemIntoLongException(t *testing.T) {
+	p := New(Options{Exceptions: map[string]string{"mice": "mousekind"}})
+	buf := make([]rune, 0, 16)
+	for _, into := range []func(buf, s []rune) []rune{p.StemInto, p.StemWithoutLowerCasingInto} {
+		stem := into(buf, []rune("mice"))
+		if string(stem) != "mousekind" {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", "mice", string(stem), "mousekind")
+		}
+		if &stem[0] != &buf[:1][0] {
+			t.Errorf("the stem of %q does not share memory with buf", "mice")
+		}
+	}
+
+	// A buf too small for the stem is grown.
+	small := make([]rune, 0, 4)
+	if stem := p.StemInto(small, []rune("MICE")); string(stem) != "mousekind" {
+		t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", "MICE", string(stem), "mousekind")
+	}
+}
+
+func BenchmarkRuneSliceInto(b *testing.B) {
+	ss := getVoc()
+	rs := make([][]rune, len(ss))
+	for i, s := range ss {
+		rs[i] = []rune(s)
+	}
+	var buf []rune
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, s := range rs {
+			buf = StemInto(buf, s)
+		}
+	}
+}
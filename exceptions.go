@@ -0,0 +1,64 @@
+package porter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ReadExceptions reads exceptions and protected words into o, adding to any
+// that are already there.
+//
+// The format is one word per line.  A line with a single word protects that
+// word.  A line with two words makes the first an exception, with the second
+// as its stem.  Blank lines, and anything after a '#', are ignored.  For
+// example:
+//
+//	# brands
+//	wells
+//	news
+//
+//	# irregular forms
+//	mice    mouse
+//	geese   goose
+func (o *Options) ReadExceptions(r io.Reader) error {
+	if o.Exceptions == nil {
+		o.Exceptions = make(map[string]string)
+	}
+	if o.Protected == nil {
+		o.Protected = make(map[string]bool)
+	}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		switch fields := strings.Fields(line); len(fields) {
+		case 0:
+		case 1:
+			o.Protected[strings.ToLower(fields[0])] = true
+		case 2:
+			o.Exceptions[strings.ToLower(fields[0])] = fields[1]
+		default:
+			return fmt.Errorf("line %d: expected a word, or a word and its stem, but got %q", n, line)
+		}
+	}
+	return scanner.Err()
+}
+
+// LoadExceptions reads exceptions and protected words from a file, in the
+// format described by ReadExceptions.
+func (o *Options) LoadExceptions(filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := o.ReadExceptions(f); err != nil {
+		return fmt.Errorf("%s: %v", filename, err)
+	}
+	return nil
+}
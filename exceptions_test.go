@@ -0,0 +1,111 @@
+package porter
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLoadExceptions(t *testing.T) {
+	var opts Options
+	if err := opts.LoadExceptions(filepath.Join(testDir, "exceptions.txt")); err != nil {
+		t.Fatalf("%s", err)
+	}
+	p := New(opts)
+	tests := []struct {
+		s, exp string
+	}{
+		{"Wells", "wells"},
+		{"NEWS", "news"},
+		{"gaming", "gaming"},
+		{"games", "game"},
+		{"Mice", "mouse"},
+		{"geese", "goose"},
+		{"children", "child"},
+		{"generalizations", "gener"},
+	}
+	for _, test := range tests {
+		if stem := p.StemString(test.s); stem != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.s, stem, test.exp)
+		}
+		if tr := p.StemTrace(test.s); tr.Stem != test.exp {
+			t.Errorf("StemTrace(%q).Stem = %q, expected %q", test.s, tr.Stem, test.exp)
+		}
+	}
+	if stem := StemString("Wells"); stem != "well" {
+		t.Errorf("exceptions leaked into StemString: got %q", stem)
+	}
+}
+
+func TestReadExceptionsErrors(t *testing.T) {
+	var opts Options
+	err := opts.ReadExceptions(strings.NewReader("ok\none two three\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("ReadExceptions returned %v, expected an error for line 2", err)
+	}
+	if err := opts.LoadExceptions(filepath.Join(testDir, "does-not-exist.txt")); err == nil {
+		t.Errorf("LoadExceptions of a missing file did not fail")
+	}
+}
+
+func TestExceptionsCopied(t *testing.T) {
+	opts := Options{
+		Exceptions: map[string]string{"oxen": "ox"},
+		Protected:  map[string]bool{"gaming": true},
+	}
+	p := New(opts)
+	opts.Exceptions["oxen"] = "oxe"
+	delete(opts.Protected, "gaming")
+	if stem := p.StemString("oxen"); stem != "ox" {
+		t.Errorf("StemString(\"oxen\") = %q, expected \"ox\"", stem)
+	}
+	if stem := p.StemString("gaming"); stem != "gaming" {
+		t.Errorf("StemString(\"gaming\") = %q, expected \"gaming\"", stem)
+	}
+	if stem := string(p.StemWithoutLowerCasing([]rune("ox"))); stem != "ox" {
+		t.Errorf("StemWithoutLowerCasing(\"ox\") = %q, expected \"ox\"", stem)
+	}
+}
+
+func TestExceptionsSubslice(t *testing.T) {
+	p := New(Options{Exceptions: map[string]string{"mice": "mouse", "geese": "goose", "oxen": "ox"}})
+	tests := []struct {
+		s, exp string
+	}{
+		{"mice", "mouse"},
+		{"geese", "goose"},
+		{"oxen", "ox"},
+	}
+	for _, test := range tests {
+		buf := []rune(test.s + " and more")
+		n := len([]rune(test.s))
+		if stem := string(p.StemWithoutLowerCasing(buf[:n])); stem != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.s, stem, test.exp)
+		}
+		if tail := string(buf[n:]); tail != " and more" {
+			t.Errorf("stemming [%s] overwrote the runes after it: [%s]", test.s, tail)
+		}
+	}
+}
+
+func TestExceptionsConcurrent(t *testing.T) {
+	p := New(Options{
+		Exceptions: map[string]string{"mice": "mouse"},
+		Protected:  map[string]bool{"news": true},
+	})
+	voc := getVoc()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, word := range voc {
+				if stem, exp := p.StemString(word), StemString(word); word != "mice" && word != "news" && stem != exp {
+					t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", word, stem, exp)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
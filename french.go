@@ -0,0 +1,420 @@
+package porter
+
+import (
+	"unicode"
+)
+
+// This file implements the French stemmer from the Snowball project.  For the
+// algorithm, see:
+//
+// http://snowball.tartarus.org/algorithms/french/stemmer.html
+//
+// That is the algorithm of Snowball before release 2.0.0.  Later releases no
+// longer take ï for a vowel, and remove the ë of a final guë, so they stem
+// "aiguë" to "aigu" and "ambiguïtés" to "ambigu", where this gives "aiguë" and
+// "ambiguït".
+//
+// Where u, i and y are to be treated as consonants, they are marked by
+// changing them to upper case, and changed back once the word is stemmed.
+// Like the other stemmers, it works on the []rune it is given.  A rule may
+// replace a suffix with a longer one, but only once a longer suffix has been
+// removed, so the stem is never longer than the word.
+
+// frenchRVPrefixes are the beginnings of words after which RV starts.
+var frenchRVPrefixes = []string{"par", "col", "tap"}
+
+// The suffixes of each step.  Only the longest suffix that a word ends with
+// is ever considered.
+var (
+	frenchStandardSuffixes = []string{
+		"ance", "iqUe", "isme", "able", "iste", "eux",
+		"ances", "iqUes", "ismes", "ables", "istes",
+		"atrice", "ateur", "ation", "atrices", "ateurs", "ations",
+		"logie", "logies",
+		"usion", "ution", "usions", "utions",
+		"ence", "ences",
+		"ement", "ements",
+		"ité", "ités",
+		"if", "ive", "ifs", "ives",
+		"eaux",
+		"aux",
+		"euse", "euses",
+		"issement", "issements",
+		"amment",
+		"emment",
+		"ment", "ments",
+	}
+	frenchIVerbSuffixes = []string{
+		"îmes", "ît", "îtes", "i", "ie", "ies", "ir", "ira", "irai", "iraIent",
+		"irais", "irait", "iras", "irent", "irez", "iriez", "irions", "irons",
+		"iront", "is", "issaIent", "issais", "issait", "issant", "issante",
+		"issantes", "issants", "isse", "issent", "isses", "issez", "issiez",
+		"issions", "issons", "it",
+	}
+	frenchVerbSuffixes = []string{
+		"ions",
+		"é", "ée", "ées", "és", "èrent", "er", "era", "erai", "eraIent",
+		"erais", "erait", "eras", "erez", "eriez", "erions", "erons", "eront",
+		"ez", "iez",
+		"âmes", "ât", "âtes", "a", "ai", "aIent", "ais", "ait", "ant", "ante",
+		"antes", "ants", "as", "asse", "assent", "asses", "assiez", "assions",
+	}
+	frenchResidualSuffixes = []string{
+		"ion",
+		"ier", "ière", "Ier", "Ière",
+		"e",
+		"ë",
+	}
+	frenchDoubleEndings = []string{"enn", "onn", "ett", "ell", "eill"}
+)
+
+// isFrenchVowel returns true if the rune is a vowel.  The upper case U, I and
+// Y of a marked word are not vowels.
+func isFrenchVowel(r rune) bool {
+	switch r {
+	case 'a', 'e', 'i', 'o', 'u', 'y', 'â', 'à', 'ë', 'é', 'ê', 'è', 'ï', 'î', 'ô', 'û', 'ù':
+		return true
+	}
+	return false
+}
+
+// frenchPrelude marks u and i between vowels, y next to a vowel, and u after
+// q, by changing them to upper case.
+func frenchPrelude(s []rune) {
+	for i := 0; i < len(s); i++ {
+		next := i+1 < len(s)
+		switch {
+		case next && isFrenchVowel(s[i]) && (s[i+1] == 'u' || s[i+1] == 'i') && i+2 < len(s) && isFrenchVowel(s[i+2]):
+			s[i+1] = unicode.ToUpper(s[i+1])
+		case next && isFrenchVowel(s[i]) && s[i+1] == 'y':
+			s[i+1] = 'Y'
+		case next && s[i] == 'y' && isFrenchVowel(s[i+1]):
+			s[i] = 'Y'
+		case next && s[i] == 'q' && s[i+1] == 'u':
+			s[i+1] = 'U'
+		}
+	}
+}
+
+// frenchRegions returns the start of the RV, R1 and R2 regions.
+func frenchRegions(s []rune) (rv, r1, r2 int) {
+	rv = frenchRVStart(s)
+	r1 = regionStart(s, 0, isFrenchVowel)
+	return rv, r1, regionStart(s, r1, isFrenchVowel)
+}
+
+// frenchRVStart returns the start of RV.  It starts after the third letter if
+// the word begins with two vowels, after par, col or tap, and otherwise after
+// the first vowel that is not the first letter.
+func frenchRVStart(s []rune) int {
+	if len(s) >= 3 && isFrenchVowel(s[0]) && isFrenchVowel(s[1]) {
+		return 3
+	}
+	for _, prefix := range frenchRVPrefixes {
+		if len(s) >= len(prefix) && endsWith(s[:len(prefix)], prefix) {
+			return len(prefix)
+		}
+	}
+	for i := 1; i < len(s); i++ {
+		if isFrenchVowel(s[i]) {
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// frenchIC removes an ic at the end of the word if it is in R2, and otherwise
+// replaces it with iqU.
+func frenchIC(s []rune, r2 int) []rune {
+	if len(s)-2 >= r2 {
+		return s[:len(s)-2]
+	}
+	return replaceSuffix(s, 2, "iqU")
+}
+
+// frenchStandardSuffix removes or replaces the longest standard suffix.  It
+// returns false if no suffix was removed, or if the suffix was one that lets
+// the verb suffixes be tried next, though the word may still have changed.
+func frenchStandardSuffix(s []rune, rv, r1, r2 int) ([]rune, bool) {
+	suffix, i := longestSuffix(s, 0, frenchStandardSuffixes)
+	switch suffix {
+	case "":
+		return s, false
+	case "ance", "iqUe", "isme", "able", "iste", "eux", "ances", "iqUes", "ismes", "ables", "istes":
+		if i < r2 {
+			return s, false
+		}
+		s = s[:i]
+	case "atrice", "ateur", "ation", "atrices", "ateurs", "ations":
+		if i < r2 {
+			return s, false
+		}
+		s = s[:i]
+		if endsWithString(s, "ic") {
+			s = frenchIC(s, r2)
+		}
+	case "logie", "logies":
+		if i < r2 {
+			return s, false
+		}
+		s = replaceSuffix(s, len(s)-i, "log")
+	case "usion", "ution", "usions", "utions":
+		if i < r2 {
+			return s, false
+		}
+		s = replaceSuffix(s, len(s)-i, "u")
+	case "ence", "ences":
+		if i < r2 {
+			return s, false
+		}
+		s = replaceSuffix(s, len(s)-i, "ent")
+	case "ement", "ements":
+		if i < rv {
+			return s, false
+		}
+		s = s[:i]
+		suffix, i = longestSuffix(s, 0, []string{"iv", "eus", "abl", "iqU", "ièr", "Ièr"})
+		switch suffix {
+		case "iv":
+			if i >= r2 {
+				s = s[:i]
+				if endsWithString(s, "at") && len(s)-2 >= r2 {
+					s = s[:len(s)-2]
+				}
+			}
+		case "eus":
+			if i >= r2 {
+				s = s[:i]
+			} else if i >= r1 {
+				s = replaceSuffix(s, 3, "eux")
+			}
+		case "abl", "iqU":
+			if i >= r2 {
+				s = s[:i]
+			}
+		case "ièr", "Ièr":
+			if i >= rv {
+				s = replaceSuffix(s, 3, "i")
+			}
+		}
+	case "ité", "ités":
+		if i < r2 {
+			return s, false
+		}
+		s = s[:i]
+		suffix, i = longestSuffix(s, 0, []string{"abil", "ic", "iv"})
+		switch suffix {
+		case "abil":
+			if i >= r2 {
+				s = s[:i]
+			} else {
+				s = replaceSuffix(s, 4, "abl")
+			}
+		case "ic":
+			s = frenchIC(s, r2)
+		case "iv":
+			if i >= r2 {
+				s = s[:i]
+			}
+		}
+	case "if", "ive", "ifs", "ives":
+		if i < r2 {
+			return s, false
+		}
+		s = s[:i]
+		if endsWithString(s, "at") && len(s)-2 >= r2 {
+			s = s[:len(s)-2]
+			if endsWithString(s, "ic") {
+				s = frenchIC(s, r2)
+			}
+		}
+	case "eaux":
+		s = s[:len(s)-1]
+	case "aux":
+		if i < r1 {
+			return s, false
+		}
+		s = replaceSuffix(s, 3, "al")
+	case "euse", "euses":
+		switch {
+		case i >= r2:
+			s = s[:i]
+		case i >= r1:
+			s = replaceSuffix(s, len(s)-i, "eux")
+		default:
+			return s, false
+		}
+	case "issement", "issements":
+		if i < r1 || i == 0 || isFrenchVowel(s[i-1]) {
+			return s, false
+		}
+		s = s[:i]
+	case "amment":
+		if i >= rv {
+			s = replaceSuffix(s, 6, "ant")
+		}
+		return s, false
+	case "emment":
+		if i >= rv {
+			s = replaceSuffix(s, 6, "ent")
+		}
+		return s, false
+	case "ment", "ments":
+		if i > rv && isFrenchVowel(s[i-1]) {
+			s = s[:i]
+		}
+		return s, false
+	}
+	return s, true
+}
+
+// frenchIVerbSuffix removes the longest suffix in RV of a verb whose
+// infinitive ends in -ir, if it follows a non-vowel.
+func frenchIVerbSuffix(s []rune, rv int) ([]rune, bool) {
+	suffix, i := longestSuffix(s, rv, frenchIVerbSuffixes)
+	if suffix == "" || i <= rv || isFrenchVowel(s[i-1]) {
+		return s, false
+	}
+	return s[:i], true
+}
+
+// frenchVerbSuffix removes the longest suffix in RV of any other verb.
+func frenchVerbSuffix(s []rune, rv, r2 int) ([]rune, bool) {
+	suffix, i := longestSuffix(s, rv, frenchVerbSuffixes)
+	switch suffix {
+	case "":
+		return s, false
+	case "ions":
+		if i < r2 {
+			return s, false
+		}
+		s = s[:i]
+	case "âmes", "ât", "âtes", "a", "ai", "aIent", "ais", "ait", "ant", "ante",
+		"antes", "ants", "as", "asse", "assent", "asses", "assiez", "assions":
+		s = s[:i]
+		if i > rv && s[i-1] == 'e' {
+			s = s[:i-1]
+		}
+	default:
+		s = s[:i]
+	}
+	return s, true
+}
+
+// frenchResidualSuffix removes a final s, unless it follows a, i, o, u, è or
+// s, then the longest residual suffix in RV.  It is only used when no other
+// suffix was removed.
+func frenchResidualSuffix(s []rune, rv, r2 int) []rune {
+	if n := len(s); n >= 2 && s[n-1] == 's' {
+		switch s[n-2] {
+		case 'a', 'i', 'o', 'u', 'è', 's':
+		default:
+			s = s[:n-1]
+		}
+	}
+	suffix, i := longestSuffix(s, rv, frenchResidualSuffixes)
+	switch suffix {
+	case "ion":
+		if i >= r2 && i > rv && (s[i-1] == 's' || s[i-1] == 't') {
+			s = s[:i]
+		}
+	case "ier", "ière", "Ier", "Ière":
+		s = replaceSuffix(s, len(s)-i, "i")
+	case "e":
+		s = s[:i]
+	case "ë":
+		if i-2 >= rv && s[i-2] == 'g' && s[i-1] == 'u' {
+			s = s[:i]
+		}
+	}
+	return s
+}
+
+// frenchUnDouble removes the last letter of a word that ends in enn, onn,
+// ett, ell or eill.
+func frenchUnDouble(s []rune) []rune {
+	for _, ending := range frenchDoubleEndings {
+		if endsWithString(s, ending) {
+			return s[:len(s)-1]
+		}
+	}
+	return s
+}
+
+// frenchUnAccent replaces an é or è followed only by non-vowels, of which
+// there is at least one, with e.
+func frenchUnAccent(s []rune) {
+	i := len(s)
+	for i > 0 && !isFrenchVowel(s[i-1]) {
+		i--
+	}
+	if i > 0 && i < len(s) && (s[i-1] == 'é' || s[i-1] == 'è') {
+		s[i-1] = 'e'
+	}
+}
+
+// FrenchStemString converts a string to a rune array, then stems the result
+// with the French algorithm.
+func FrenchStemString(s string) string {
+	ra := []rune(s)
+	ra = FrenchStem(ra)
+	return string(ra)
+}
+
+// FrenchStem converts the runes to lower case, then stems the lowercase runes
+// with the French algorithm.
+func FrenchStem(s []rune) []rune {
+	if len(s) == 0 {
+		return s
+	}
+	for i := 0; i < len(s); i++ {
+		s[i] = unicode.ToLower(s[i])
+	}
+	return FrenchStemWithoutLowerCasing(s)
+}
+
+// FrenchStemWithoutLowerCasing applies the French stemming assuming that the
+// runes are lowercase.
+func FrenchStemWithoutLowerCasing(s []rune) []rune {
+	frenchPrelude(s)
+	rv, r1, r2 := frenchRegions(s)
+
+	s, ok := frenchStandardSuffix(s, rv, r1, r2)
+	if !ok {
+		s, ok = frenchIVerbSuffix(s, rv)
+	}
+	if !ok {
+		s, ok = frenchVerbSuffix(s, rv, r2)
+	}
+	if n := len(s); ok && n > 0 {
+		switch s[n-1] {
+		case 'Y':
+			s[n-1] = 'i'
+		case 'ç':
+			s[n-1] = 'c'
+		}
+	} else if !ok {
+		s = frenchResidualSuffix(s, rv, r2)
+	}
+	s = frenchUnDouble(s)
+	frenchUnAccent(s)
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case 'I':
+			s[i] = 'i'
+		case 'U':
+			s[i] = 'u'
+		case 'Y':
+			s[i] = 'y'
+		}
+	}
+	return s
+}
+
+// french is the French algorithm as a Stemmer.
+type french struct{}
+
+func (french) StemString(s string) string             { return FrenchStemString(s) }
+func (french) Stem(s []rune) []rune                   { return FrenchStem(s) }
+func (french) StemWithoutLowerCasing(s []rune) []rune { return FrenchStemWithoutLowerCasing(s) }
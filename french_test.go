@@ -0,0 +1,133 @@
+package porter
+
+import (
+	"testing"
+)
+
+func TestFrenchPrelude(t *testing.T) {
+	tests := []struct {
+		s, exp string
+	}{
+		{"jouer", "joUer"},
+		{"ennuie", "ennuIe"},
+		{"yeux", "Yeux"},
+		{"quand", "qUand"},
+		{"croyiez", "croYiez"},
+		{"ouie", "oUie"},
+	}
+	for _, test := range tests {
+		s := []rune(test.s)
+		frenchPrelude(s)
+		if string(s) != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.s, string(s), test.exp)
+		}
+	}
+}
+
+func TestFrenchRegions(t *testing.T) {
+	tests := []struct {
+		s          string
+		rv, r1, r2 string
+	}{
+		{"fameusement", "meusement", "eusement", "ement"},
+		{"aimer", "er", "er", ""},
+		{"adorer", "rer", "orer", "er"},
+		{"voler", "ler", "er", ""},
+		{"tapis", "is", "is", ""},
+		{"parade", "ade", "ade", "e"},
+		{"colet", "et", "et", ""},
+		{"au", "", "", ""},
+	}
+	for _, test := range tests {
+		s := []rune(test.s)
+		rv, r1, r2 := frenchRegions(s)
+		if string(s[rv:]) != test.rv || string(s[r1:]) != test.r1 || string(s[r2:]) != test.r2 {
+			t.Errorf("Did NOT get what was expected for calling frenchRegions() on [%s]. Expect RV [%s], R1 [%s] and R2 [%s] but got [%s], [%s] and [%s]", test.s, test.rv, test.r1, test.r2, string(s[rv:]), string(s[r1:]), string(s[r2:]))
+		}
+	}
+}
+
+func TestFrenchStemString(t *testing.T) {
+	tests := []struct {
+		s, exp string
+	}{
+		{"", ""},
+		{"a", "a"},
+		{"Continuation", "continu"},
+		{"continuaient", "continu"},
+		{"continuellement", "continuel"},
+		{"maintenant", "mainten"},
+		{"maintenance", "mainten"},
+		{"massivement", "massiv"},
+		{"nationalité", "national"},
+		{"crédibilité", "crédibil"},
+		{"chevaux", "cheval"},
+		{"estivaux", "estival"},
+		{"yeux", "yeux"},
+		{"parlerions", "parl"},
+		{"finissons", "fin"},
+		{"connaissance", "connaiss"},
+		{"abondamment", "abond"},
+		{"évidemment", "évident"},
+		{"étrangère", "étranger"},
+		{"familière", "famili"},
+		{"brièvement", "briev"},
+		{"canines", "canin"},
+		{"couronne", "couron"},
+		{"impressionnées", "impression"},
+		{"croyiez", "croi"},
+	}
+	for _, test := range tests {
+		if stem := FrenchStemString(test.s); stem != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.s, stem, test.exp)
+		}
+	}
+}
+
+// TestFrenchSnowball2 checks the words that Snowball 2.0.0 and later stem
+// differently, as they changed how ë and ï are handled.  This package follows
+// the algorithm before 2.0.0.
+func TestFrenchSnowball2(t *testing.T) {
+	tests := []struct {
+		s, exp, snowball2 string
+	}{
+		{"aiguë", "aiguë", "aigu"},
+		{"canoë", "canoë", "cano"},
+		{"ambiguïtés", "ambiguït", "ambigu"},
+		{"exiguïté", "exiguït", "exigu"},
+		{"héroïque", "héroïqu", "héro"},
+		{"ambiguë", "ambigu", "ambigu"},
+		{"noëls", "noël", "noël"},
+		{"naïve", "naïv", "naïv"},
+	}
+	for _, test := range tests {
+		if stem := FrenchStemString(test.s); stem != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s] (Snowball 2 gives [%s])", test.s, stem, test.exp, test.snowball2)
+		}
+	}
+}
+
+func TestFrenchVocabulary(t *testing.T) {
+	vs := readFields(t, "french_voc.txt")
+	os := readFields(t, "french_output.txt")
+	if len(vs) != len(os) {
+		t.Fatalf("vocabulary has %d words but output has %d stems", len(vs), len(os))
+	}
+	for i, word := range vs {
+		stem := FrenchStemString(word)
+		if stem != os[i] {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", word, stem, os[i])
+		}
+	}
+}
+
+func BenchmarkFrenchString(b *testing.B) {
+	ss := readFields(b, "french_voc.txt")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, s := range ss {
+			stem := FrenchStemString(s)
+			_ = stem
+		}
+	}
+}
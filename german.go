@@ -0,0 +1,287 @@
+package porter
+
+import (
+	"unicode"
+)
+
+// This file implements the German stemmer from the Snowball project, and its
+// german2 variant.  For the algorithms, see:
+//
+// http://snowball.tartarus.org/algorithms/german/stemmer.html
+// http://snowball.tartarus.org/algorithms/german2/stemmer.html
+//
+// Where u and y are between vowels, they are marked as consonants by changing
+// them to upper case, and changed back once the word is stemmed.  The stem has
+// no umlauts: ä, ö and ü become a, o and u.  It works on the []rune it is
+// given, except that each ß is replaced by ss, so a word with a ß may be
+// stemmed into new runes, and its stem may be longer than the word.
+
+// The suffixes of each step.  Only the longest suffix that a word ends with
+// is ever considered.
+var (
+	germanStep1Suffixes = []string{"em", "ern", "er", "e", "en", "es", "s"}
+	germanStep2Suffixes = []string{"en", "er", "est", "st"}
+	germanStep3Suffixes = []string{"end", "ung", "ig", "ik", "isch", "lich", "heit", "keit"}
+)
+
+// GermanOptions configures a German stemmer.
+type GermanOptions struct {
+	// Variant2 is the german2 variant of the algorithm, for text where
+	// umlauts are written as a digraph.  It replaces ae, oe and ue with ä, ö
+	// and ü before stemming, except for the ue of que.
+	Variant2 bool
+}
+
+// German is a configurable German stemmer.  The zero value is the Snowball
+// German algorithm, the same as GermanStemString, GermanStem and
+// GermanStemWithoutLowerCasing.
+//
+// A German is safe for concurrent use.
+type German struct {
+	opts GermanOptions
+}
+
+var defaultGerman = &German{}
+
+// NewGerman returns a German stemmer configured with opts.
+func NewGerman(opts GermanOptions) *German {
+	return &German{opts: opts}
+}
+
+// isGermanVowel returns true if the rune is a vowel.  The upper case U and Y
+// of a marked word are not vowels.
+func isGermanVowel(r rune) bool {
+	switch r {
+	case 'a', 'e', 'i', 'o', 'u', 'y', 'ä', 'ö', 'ü':
+		return true
+	}
+	return false
+}
+
+// isGermanSEnding returns true if the rune is one of the letters that an
+// s ending can be removed after.
+func isGermanSEnding(r rune) bool {
+	switch r {
+	case 'b', 'd', 'f', 'g', 'h', 'k', 'l', 'm', 'n', 'r', 't':
+		return true
+	}
+	return false
+}
+
+// isGermanStEnding returns true if the rune is one of the letters that an
+// st ending can be removed after: those of an s ending, except r.
+func isGermanStEnding(r rune) bool {
+	return r != 'r' && isGermanSEnding(r)
+}
+
+// germanSharpS replaces each ß with ss.  If there are any, the word is copied
+// into new runes.
+func germanSharpS(s []rune) []rune {
+	n := 0
+	for _, r := range s {
+		if r == 'ß' {
+			n++
+		}
+	}
+	if n == 0 {
+		return s
+	}
+	t := make([]rune, 0, len(s)+n)
+	for _, r := range s {
+		if r == 'ß' {
+			t = append(t, 's', 's')
+		} else {
+			t = append(t, r)
+		}
+	}
+	return t
+}
+
+// germanPrelude marks u and y between vowels by changing them to upper case.
+func germanPrelude(s []rune) {
+	for i := 1; i+1 < len(s); i++ {
+		if !isGermanVowel(s[i-1]) || !isGermanVowel(s[i+1]) {
+			continue
+		}
+		switch s[i] {
+		case 'u':
+			s[i] = 'U'
+		case 'y':
+			s[i] = 'Y'
+		}
+	}
+}
+
+// german2Umlauts replaces ae, oe and ue with ä, ö and ü, except for the ue
+// of que.  The word is shortened in place.
+func german2Umlauts(s []rune) []rune {
+	j := 0
+	for i := 0; i < len(s); i, j = i+1, j+1 {
+		s[j] = s[i]
+		if i+1 == len(s) {
+			continue
+		}
+		switch {
+		case s[i] == 'q' && s[i+1] == 'u':
+			i, j = i+1, j+1
+			s[j] = s[i]
+		case s[i] == 'a' && s[i+1] == 'e':
+			s[j] = 'ä'
+			i++
+		case s[i] == 'o' && s[i+1] == 'e':
+			s[j] = 'ö'
+			i++
+		case s[i] == 'u' && s[i+1] == 'e':
+			s[j] = 'ü'
+			i++
+		}
+	}
+	return s[:j]
+}
+
+// germanRegions returns the start of the R1 and R2 regions.  R1 starts after
+// the third letter at the earliest, but R2 is found from where R1 would have
+// started otherwise.
+func germanRegions(s []rune) (r1, r2 int) {
+	if len(s) < 3 {
+		return len(s), len(s)
+	}
+	r1 = regionStart(s, 0, isGermanVowel)
+	r2 = regionStart(s, r1, isGermanVowel)
+	if r1 < 3 {
+		r1 = 3
+	}
+	return r1, r2
+}
+
+// germanStep1 removes an inflectional ending in R1.  An s is only removed
+// after a valid s ending, and the ending of a word in -nisse leaves -nis.
+func germanStep1(s []rune, r1 int) []rune {
+	suffix, i := longestSuffix(s, 0, germanStep1Suffixes)
+	if suffix == "" || i < r1 {
+		return s
+	}
+	switch suffix {
+	case "e", "en", "es":
+		s = s[:i]
+		if endsWithString(s, "niss") {
+			s = s[:i-1]
+		}
+	case "s":
+		if i > 0 && isGermanSEnding(s[i-1]) {
+			s = s[:i]
+		}
+	default:
+		s = s[:i]
+	}
+	return s
+}
+
+// germanStep2 removes an en, er or est in R1, or an st in R1 that follows a
+// valid st ending which is itself preceded by at least three letters.
+func germanStep2(s []rune, r1 int) []rune {
+	suffix, i := longestSuffix(s, 0, germanStep2Suffixes)
+	if suffix == "" || i < r1 {
+		return s
+	}
+	if suffix == "st" && (i < 4 || !isGermanStEnding(s[i-1])) {
+		return s
+	}
+	return s[:i]
+}
+
+// germanStep3 removes a derivational suffix in R2, and then what the suffix
+// may have followed.
+func germanStep3(s []rune, r1, r2 int) []rune {
+	suffix, i := longestSuffix(s, 0, germanStep3Suffixes)
+	if suffix == "" || i < r2 {
+		return s
+	}
+	switch suffix {
+	case "end", "ung":
+		s = s[:i]
+		if endsWithString(s, "ig") && i-2 >= r2 && (i < 3 || s[i-3] != 'e') {
+			s = s[:i-2]
+		}
+	case "ig", "ik", "isch":
+		if i == 0 || s[i-1] != 'e' {
+			s = s[:i]
+		}
+	case "lich", "heit":
+		s = s[:i]
+		if (endsWithString(s, "er") || endsWithString(s, "en")) && i-2 >= r1 {
+			s = s[:i-2]
+		}
+	case "keit":
+		s = s[:i]
+		if suffix, i = longestSuffix(s, 0, []string{"lich", "ig"}); suffix != "" && i >= r2 {
+			s = s[:i]
+		}
+	}
+	return s
+}
+
+// StemString converts a string to a rune array, then stems the result.
+func (g *German) StemString(s string) string {
+	ra := []rune(s)
+	ra = g.Stem(ra)
+	return string(ra)
+}
+
+// Stem converts the runes to lower case, then stems the lowercase runes.
+func (g *German) Stem(s []rune) []rune {
+	if len(s) == 0 {
+		return s
+	}
+	for i := 0; i < len(s); i++ {
+		s[i] = unicode.ToLower(s[i])
+	}
+	return g.StemWithoutLowerCasing(s)
+}
+
+// StemWithoutLowerCasing applies the stemming assuming that the runes are
+// lowercase.
+func (g *German) StemWithoutLowerCasing(s []rune) []rune {
+	s = germanSharpS(s)
+	germanPrelude(s)
+	if g.opts.Variant2 {
+		s = german2Umlauts(s)
+	}
+	r1, r2 := germanRegions(s)
+
+	s = germanStep1(s, r1)
+	s = germanStep2(s, r1)
+	s = germanStep3(s, r1, r2)
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case 'U', 'ü':
+			s[i] = 'u'
+		case 'Y':
+			s[i] = 'y'
+		case 'ä':
+			s[i] = 'a'
+		case 'ö':
+			s[i] = 'o'
+		}
+	}
+	return s
+}
+
+// GermanStemString converts a string to a rune array, then stems the result
+// with the German algorithm.
+func GermanStemString(s string) string {
+	return defaultGerman.StemString(s)
+}
+
+// GermanStem converts the runes to lower case, then stems the lowercase runes
+// with the German algorithm.
+func GermanStem(s []rune) []rune {
+	return defaultGerman.Stem(s)
+}
+
+// GermanStemWithoutLowerCasing applies the German stemming assuming that the
+// runes are lowercase.
+func GermanStemWithoutLowerCasing(s []rune) []rune {
+	return defaultGerman.StemWithoutLowerCasing(s)
+}
@@ -0,0 +1,161 @@
+package porter
+
+import (
+	"testing"
+)
+
+func TestGermanPrelude(t *testing.T) {
+	tests := []struct {
+		s, exp string
+	}{
+		{"feuer", "feUer"},
+		{"bayern", "baYern"},
+		{"quelle", "quelle"},
+		{"aua", "aUa"},
+		{"uuu", "uUu"},
+	}
+	for _, test := range tests {
+		s := []rune(test.s)
+		germanPrelude(s)
+		if string(s) != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.s, string(s), test.exp)
+		}
+	}
+}
+
+func TestGerman2Umlauts(t *testing.T) {
+	tests := []struct {
+		s, exp string
+	}{
+		{"mueller", "müller"},
+		{"schoen", "schön"},
+		{"aequivalent", "äquivalent"},
+		{"quelle", "quelle"},
+		{"quae", "quä"},
+		{"feUer", "feUer"},
+		{"ue", "ü"},
+	}
+	for _, test := range tests {
+		if s := string(german2Umlauts([]rune(test.s))); s != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.s, s, test.exp)
+		}
+	}
+}
+
+func TestGermanRegions(t *testing.T) {
+	tests := []struct {
+		s      string
+		r1, r2 string
+	}{
+		{"kategorien", "egorien", "orien"},
+		{"arbeit", "eit", ""},
+		{"ende", "e", ""},
+		{"feUer", "er", ""},
+		{"ab", "", ""},
+	}
+	for _, test := range tests {
+		s := []rune(test.s)
+		r1, r2 := germanRegions(s)
+		if string(s[r1:]) != test.r1 || string(s[r2:]) != test.r2 {
+			t.Errorf("Did NOT get what was expected for calling germanRegions() on [%s]. Expect R1 [%s] and R2 [%s] but got [%s] and [%s]", test.s, test.r1, test.r2, string(s[r1:]), string(s[r2:]))
+		}
+	}
+}
+
+func TestGermanStemString(t *testing.T) {
+	tests := []struct {
+		s, exp string
+	}{
+		{"", ""},
+		{"ab", "ab"},
+		{"Aufeinanderfolgenden", "aufeinanderfolg"},
+		{"kategorien", "kategori"},
+		{"straße", "strass"},
+		{"STRASSEN", "strass"},
+		{"häuser", "haus"},
+		{"kenntnisse", "kenntnis"},
+		{"freundlichkeit", "freundlich"},
+		{"bedeutung", "bedeut"},
+		{"wichtigsten", "wichtig"},
+		{"erstellst", "erstell"},
+		{"feuer", "feu"},
+		{"bayern", "bay"},
+		{"befestigung", "befest"},
+		{"lächerlichkeit", "lacher"},
+		{"natürlich", "natur"},
+		{"verständlichkeit", "verstand"},
+		{"kurzfristig", "kurzfrist"},
+		{"möglichkeit", "moglich"},
+		{"mueller", "muell"},
+	}
+	for _, test := range tests {
+		if stem := GermanStemString(test.s); stem != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.s, stem, test.exp)
+		}
+	}
+}
+
+func TestGerman2StemString(t *testing.T) {
+	tests := []struct {
+		s, exp string
+	}{
+		{"mueller", "mull"},
+		{"Müller", "mull"},
+		{"quelle", "quell"},
+		{"schoenheit", "schonheit"},
+		{"aequivalent", "aquivalent"},
+		{"fuesse", "fuss"},
+		{"füße", "fuss"},
+		{"feuer", "feu"},
+	}
+	g := NewGerman(GermanOptions{Variant2: true})
+	for _, test := range tests {
+		if stem := g.StemString(test.s); stem != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.s, stem, test.exp)
+		}
+	}
+}
+
+func TestGermanSharpS(t *testing.T) {
+	// A ß makes the stem longer than the word, so it cannot be stemmed in
+	// place.
+	s := []rune("groß")
+	stem := GermanStemWithoutLowerCasing(s)
+	if string(stem) != "gross" || string(s) != "groß" {
+		t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", string(s), string(stem), "gross")
+	}
+}
+
+func TestGermanVocabulary(t *testing.T) {
+	tests := []struct {
+		voc, output string
+		stemmer     Stemmer
+	}{
+		{"german_voc.txt", "german_output.txt", defaultGerman},
+		{"german2_voc.txt", "german2_output.txt", NewGerman(GermanOptions{Variant2: true})},
+	}
+	for _, test := range tests {
+		vs := readFields(t, test.voc)
+		os := readFields(t, test.output)
+		if len(vs) != len(os) {
+			t.Fatalf("vocabulary has %d words but output has %d stems", len(vs), len(os))
+		}
+		for i, word := range vs {
+			stem := test.stemmer.StemString(word)
+			if stem != os[i] {
+				t.Errorf("%s: Input: [%s] -> Actual: [%s]. Expected: [%s]", test.output, word, stem, os[i])
+			}
+		}
+	}
+}
+
+func BenchmarkGermanString(b *testing.B) {
+	ss := readFields(b, "german_voc.txt")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, s := range ss {
+			stem := GermanStemString(s)
+			_ = stem
+		}
+	}
+}
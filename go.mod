@@ -0,0 +1,3 @@
+module github.com/ksshannon/go-porterstemmer
+
+go 1.16
@@ -0,0 +1,69 @@
+package porter
+
+import (
+	"unicode"
+)
+
+// This file implements the S-stemmer of Harman, which only folds English
+// plurals.  For the algorithm, see:
+//
+// Donna Harman, "How effective is suffixing?", Journal of the American
+// Society for Information Science 42(1), 1991, pp. 7-15.
+//
+// Only the first rule whose ending matches is tried:
+//
+//	-ies -> -y  unless the word ends -aies or -eies
+//	-es  -> -e  unless the word ends -aes, -ees, -oes (or -ies)
+//	-s   -> -   unless the word ends -us or -ss
+//
+// Like the Porter stemmer, it leaves words of one or two letters alone, and
+// works on the []rune it is given, in place.
+
+// HarmanStemString converts a string to a rune array, then stems the result
+// with the Harman S-stemmer.
+func HarmanStemString(s string) string {
+	ra := []rune(s)
+	ra = HarmanStem(ra)
+	return string(ra)
+}
+
+// HarmanStem converts the runes to lower case, then stems the lowercase runes
+// with the Harman S-stemmer.
+func HarmanStem(s []rune) []rune {
+	if len(s) == 0 {
+		return s
+	}
+	for i := 0; i < len(s); i++ {
+		s[i] = unicode.ToLower(s[i])
+	}
+	return HarmanStemWithoutLowerCasing(s)
+}
+
+// HarmanStemWithoutLowerCasing applies the Harman S-stemmer assuming that the
+// runes are lowercase.
+func HarmanStemWithoutLowerCasing(s []rune) []rune {
+	n := len(s)
+	if n < 3 || s[n-1] != 's' {
+		return s
+	}
+	switch s[n-2] {
+	case 'u', 's':
+		return s
+	case 'e':
+		if n > 3 && s[n-3] == 'i' && s[n-4] != 'a' && s[n-4] != 'e' {
+			return replaceSuffix(s, 3, "y")
+		}
+		switch s[n-3] {
+		case 'i', 'a', 'o', 'e':
+			return s
+		}
+	}
+	return s[:n-1]
+}
+
+// harman is the Harman S-stemmer as a Stemmer.
+type harman struct{}
+
+func (harman) StemString(s string) string             { return HarmanStemString(s) }
+func (harman) Stem(s []rune) []rune                   { return HarmanStem(s) }
+func (harman) StemWithoutLowerCasing(s []rune) []rune { return HarmanStemWithoutLowerCasing(s) }
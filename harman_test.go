@@ -0,0 +1,100 @@
+package porter
+
+import (
+	"testing"
+)
+
+func TestHarmanStemString(t *testing.T) {
+	tests := []struct {
+		s, exp string
+	}{
+		{"", ""},
+		{"as", "as"},
+		{"ies", "ies"},
+		{"Ponies", "pony"},
+		{"queries", "query"},
+		{"aies", "aies"},
+		{"series", "sery"},
+		{"toes", "toes"},
+		{"trees", "trees"},
+		{"algaes", "algaes"},
+		{"caresses", "caresse"},
+		{"indexes", "indexe"},
+		{"cats", "cat"},
+		{"caress", "caress"},
+		{"corpus", "corpus"},
+		{"cat", "cat"},
+	}
+	for _, test := range tests {
+		if stem := HarmanStemString(test.s); stem != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.s, stem, test.exp)
+		}
+	}
+}
+
+// TestHarmanStep1a shows how the S-stemmer differs from step 1a of the Porter
+// algorithm, which also only removes plurals.
+func TestHarmanStep1a(t *testing.T) {
+	tests := []struct {
+		s, harman, porterStep1a string
+	}{
+		{"ponies", "pony", "poni"},
+		{"ties", "ty", "ti"},
+		{"caresses", "caresse", "caress"},
+		{"toes", "toes", "toe"},
+		{"corpus", "corpus", "corpu"},
+		{"caress", "caress", "caress"},
+		{"cats", "cat", "cat"},
+	}
+	for _, test := range tests {
+		if stem := HarmanStemString(test.s); stem != test.harman {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.s, stem, test.harman)
+		}
+		if stem := string(Step1a([]rune(test.s))); stem != test.porterStep1a {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.s, stem, test.porterStep1a)
+		}
+	}
+
+	// On the vocabulary, the S-stemmer only changes words that step 1a
+	// changes, and never removes more of them.
+	differ := 0
+	for _, word := range getVoc() {
+		harman := HarmanStemString(word)
+		porter := string(Step1a([]rune(word)))
+		if harman != word && porter == word {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", word, harman, word)
+		}
+		if len(harman) < len(porter) {
+			t.Errorf("Input: [%s] -> Actual: [%s], which is shorter than [%s]", word, harman, porter)
+		}
+		if harman != porter {
+			differ++
+		}
+	}
+	t.Logf("the S-stemmer and step 1a differ on %d words of the vocabulary", differ)
+}
+
+func TestHarmanVocabulary(t *testing.T) {
+	vs := readFields(t, "voc.txt")
+	os := readFields(t, "harman_output.txt")
+	if len(vs) != len(os) {
+		t.Fatalf("vocabulary has %d words but output has %d stems", len(vs), len(os))
+	}
+	for i, word := range vs {
+		stem := HarmanStemString(word)
+		if stem != os[i] {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", word, stem, os[i])
+		}
+	}
+}
+
+func BenchmarkHarmanString(b *testing.B) {
+	ss := getVoc()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, s := range ss {
+			stem := HarmanStemString(s)
+			_ = stem
+		}
+	}
+}
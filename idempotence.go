@@ -0,0 +1,44 @@
+package porter
+
+// Drift is a word whose stem changes when it is stemmed again.
+type Drift struct {
+	// Word is the word from the vocabulary.
+	Word string
+	// Stems are the stem of the word, the stem of that stem, and so on,
+	// until a stem stays the same or there are maxIterations of them.
+	Stems []string
+}
+
+// Stem returns the last stem of the word.
+func (d Drift) Stem() string {
+	return d.Stems[len(d.Stems)-1]
+}
+
+// NonIdempotent stems each of words once with s, then stems the stem again,
+// and returns the words whose stem changes, in the order they are given.
+// The stems of each drifting word are followed until they stop changing, or
+// there are maxIterations of them.  A maxIterations less than two is taken
+// as two.
+func NonIdempotent(s Stemmer, words []string, maxIterations int) []Drift {
+	if maxIterations < 2 {
+		maxIterations = 2
+	}
+	var drifts []Drift
+	for _, word := range words {
+		stem := s.StemString(word)
+		again := s.StemString(stem)
+		if again == stem {
+			continue
+		}
+		d := Drift{Word: word, Stems: []string{stem, again}}
+		for len(d.Stems) < maxIterations {
+			next := s.StemString(d.Stem())
+			if next == d.Stem() {
+				break
+			}
+			d.Stems = append(d.Stems, next)
+		}
+		drifts = append(drifts, d)
+	}
+	return drifts
+}
@@ -0,0 +1,82 @@
+package porter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNonIdempotent(t *testing.T) {
+	words := []string{"abuse", "generalizations", "agreed", "ponies", "accidentally"}
+	exp := []Drift{
+		{"abuse", []string{"abus", "abu"}},
+		{"agreed", []string{"agre", "agr"}},
+		{"accidentally", []string{"accident", "accid"}},
+	}
+	if drifts := NonIdempotent(defaultPorter, words, FixpointIterations); !reflect.DeepEqual(drifts, exp) {
+		t.Errorf("NonIdempotent(%q) = %v, expected %v", words, drifts, exp)
+	}
+	if drifts := NonIdempotent(New(Options{MaxIterations: FixpointIterations}), getVoc(), FixpointIterations); len(drifts) != 0 {
+		t.Errorf("NonIdempotent found %d drifting words with MaxIterations, expected none, e.g. %v", len(drifts), drifts[0])
+	}
+}
+
+func TestNonIdempotentBound(t *testing.T) {
+	for _, d := range NonIdempotent(defaultPorter, getVoc(), 2) {
+		if len(d.Stems) != 2 {
+			t.Errorf("NonIdempotent(%q, 2) has %d stems, expected 2", d.Word, len(d.Stems))
+		}
+	}
+}
+
+func TestMaxIterations(t *testing.T) {
+	tests := []struct {
+		s             string
+		maxIterations int
+		exp           string
+	}{
+		{"", FixpointIterations, ""},
+		{"abuse", 0, "abus"},
+		{"abuse", 1, "abus"},
+		{"abuse", 2, "abu"},
+		{"abuse", FixpointIterations, "abu"},
+		{"Agreed", FixpointIterations, "agr"},
+		{"ponies", FixpointIterations, "poni"},
+	}
+	for _, test := range tests {
+		p := New(Options{MaxIterations: test.maxIterations})
+		if stem := p.StemString(test.s); stem != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.s, stem, test.exp)
+		}
+	}
+}
+
+func TestMaxIterationsVocabulary(t *testing.T) {
+	p := New(Options{MaxIterations: FixpointIterations})
+	for _, word := range getVoc() {
+		stem := p.StemString(word)
+		if again := p.StemString(stem); again != stem {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", stem, again, stem)
+		}
+		// Stemming to the fixpoint by hand gives the same stem.
+		exp := StemString(word)
+		for next := StemString(exp); next != exp; next = StemString(exp) {
+			exp = next
+		}
+		if stem != exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", word, stem, exp)
+		}
+	}
+}
+
+func TestMaxIterationsAllocs(t *testing.T) {
+	p := New(Options{MaxIterations: FixpointIterations})
+	s := []rune("accidentally")
+	buf := make([]rune, len(s))
+	allocs := testing.AllocsPerRun(100, func() {
+		copy(buf, s)
+		_ = p.StemWithoutLowerCasing(buf)
+	})
+	if allocs != 0 {
+		t.Errorf("StemWithoutLowerCasing with MaxIterations made %v allocations, expected none", allocs)
+	}
+}
@@ -0,0 +1,109 @@
+// Command mklexicon builds the default KStem lexicon, kstem_lexicon.txt, from
+// words.txt, lists of English words that have nothing to do with the
+// vocabularies in testdata.  Run it from the root of the module:
+//
+//	go run ./internal/mklexicon > kstem_lexicon.txt
+//
+// KStem only stems a word that is not in its lexicon, so the lexicon must hold
+// the base forms of words, not their inflections.  A word of the lists is left
+// out if it is a regular inflection (-s, -es, -ies, -ed, -ied or -ing, with or
+// without a doubled consonant or a final e) of another word of the lists.
+// Derived words, such as "generally" or "happiness", are kept, since they are
+// words in their own right.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// readWords adds the words of a file that are only made of the letters a to
+// z.  Other lines, such as comments, are skipped.
+func readWords(name string, words map[string]bool) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		w := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if w != "" && strings.Trim(w, "abcdefghijklmnopqrstuvwxyz") == "" {
+			words[w] = true
+		}
+	}
+	return scanner.Err()
+}
+
+// undouble returns s without its last letter if it ends with a doubled
+// letter, or "".
+func undouble(s string) string {
+	if n := len(s); n >= 2 && s[n-1] == s[n-2] {
+		return s[:n-1]
+	}
+	return ""
+}
+
+// inflection returns true if w is an inflection of another of the words.
+func inflection(w string, words map[string]bool) bool {
+	has := func(bases ...string) bool {
+		for _, b := range bases {
+			if b != "" && b != w && words[b] {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case strings.HasSuffix(w, "ies"):
+		return has(w[:len(w)-3]+"y", w[:len(w)-1])
+	case strings.HasSuffix(w, "es"):
+		return has(w[:len(w)-2], w[:len(w)-1])
+	case strings.HasSuffix(w, "ss"), strings.HasSuffix(w, "us"):
+		return false
+	case strings.HasSuffix(w, "s"):
+		return has(w[:len(w)-1])
+	case strings.HasSuffix(w, "ied"):
+		return has(w[:len(w)-3]+"y", w[:len(w)-1])
+	case strings.HasSuffix(w, "ed"):
+		stem := w[:len(w)-2]
+		return has(stem, stem+"e", undouble(stem))
+	case strings.HasSuffix(w, "ing"):
+		stem := w[:len(w)-3]
+		return has(stem, stem+"e", undouble(stem))
+	}
+	return false
+}
+
+func main() {
+	name := flag.String("words", "internal/mklexicon/words.txt", "the lists of words")
+	flag.Parse()
+
+	words := make(map[string]bool)
+	if err := readWords(*name, words); err != nil {
+		fmt.Fprintf(os.Stderr, "mklexicon: %v\n", err)
+		os.Exit(1)
+	}
+	var lexicon []string
+	for w := range words {
+		if !inflection(w, words) {
+			lexicon = append(lexicon, w)
+		}
+	}
+	sort.Strings(lexicon)
+
+	out := bufio.NewWriter(os.Stdout)
+	fmt.Fprintln(out, "# The default KStem lexicon, made by internal/mklexicon from the words of")
+	fmt.Fprintln(out, "# internal/mklexicon/words.txt.  One word per line, or a word and its root.")
+	for _, w := range lexicon {
+		fmt.Fprintln(out, w)
+	}
+	if err := out.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "mklexicon: %v\n", err)
+		os.Exit(1)
+	}
+}
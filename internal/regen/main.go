@@ -0,0 +1,118 @@
+// Command regen rebuilds the golden output files in testdata from the
+// current implementation, and shows how they differ from the files in the
+// tree.  Run it from the root of the module:
+//
+//	go run ./internal/regen       # show the differences
+//	go run ./internal/regen -w    # and rewrite the files
+//
+// Review the differences before committing new golden files: any change to a
+// stem is a change to the algorithm.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	porter "github.com/ksshannon/go-porterstemmer"
+)
+
+// golden is a vocabulary file, and the file with the expected stem of each
+// of its words.
+type golden struct {
+	voc, output string
+	stem        func(string) string
+}
+
+// output.txt is not among them: it is the output of the C reference
+// implementation, which StemString departs from on purpose.
+var goldens = []golden{
+	{"voc.txt", "strict_output.txt", porter.New(porter.Options{Strict: true}).StemString},
+	{"voc.txt", "light_output.txt", porter.New(porter.Options{Level: porter.Light}).StemString},
+	{"voc.txt", "medium_output.txt", porter.New(porter.Options{Level: porter.Medium}).StemString},
+	{"porter2_voc.txt", "porter2_output.txt", porter.Porter2StemString},
+	{"voc.txt", "lancaster_output.txt", porter.LancasterStemString},
+	{"voc.txt", "lovins_output.txt", porter.LovinsStemString},
+	{"voc.txt", "harman_output.txt", porter.HarmanStemString},
+	{"voc.txt", "kstem_output.txt", porter.KrovetzStemString},
+	{"french_voc.txt", "french_output.txt", porter.FrenchStemString},
+	{"german_voc.txt", "german_output.txt", porter.GermanStemString},
+	{"german2_voc.txt", "german2_output.txt", porter.NewGerman(porter.GermanOptions{Variant2: true}).StemString},
+	{"spanish_voc.txt", "spanish_output.txt", porter.SpanishStemString},
+	{"portuguese_voc.txt", "portuguese_output.txt", porter.PortugueseStemString},
+	{"russian_voc.txt", "russian_output.txt", porter.RussianStemString},
+	{"russian_voc.txt", "russian_yo_output.txt", porter.NewRussian(porter.RussianOptions{NormalizeYo: true}).StemString},
+}
+
+// readLines returns the lines of a file, or nil if it does not exist.
+func readLines(name string) ([]string, error) {
+	data, err := ioutil.ReadFile(name)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(data)), nil
+}
+
+// regen stems the vocabulary of g, prints how the stems differ from the
+// output file, and returns the new contents of the output file.
+func regen(dir string, g golden) (data []byte, changes int, err error) {
+	words, err := readLines(filepath.Join(dir, g.voc))
+	if err != nil {
+		return nil, 0, err
+	}
+	if words == nil {
+		return nil, 0, fmt.Errorf("%s: no such file", g.voc)
+	}
+	old, err := readLines(filepath.Join(dir, g.output))
+	if err != nil {
+		return nil, 0, err
+	}
+	var buf bytes.Buffer
+	for i, word := range words {
+		stem := g.stem(word)
+		buf.WriteString(stem)
+		buf.WriteByte('\n')
+		if i >= len(old) {
+			fmt.Printf("%s: %s: + %s\n", g.output, word, stem)
+			changes++
+		} else if old[i] != stem {
+			fmt.Printf("%s: %s: - %s + %s\n", g.output, word, old[i], stem)
+			changes++
+		}
+	}
+	if len(old) > len(words) {
+		fmt.Printf("%s: %d stems removed from the end\n", g.output, len(old)-len(words))
+		changes += len(old) - len(words)
+	}
+	return buf.Bytes(), changes, nil
+}
+
+func main() {
+	dir := flag.String("dir", "testdata", "the folder with the golden files")
+	write := flag.Bool("w", false, "rewrite the output files")
+	flag.Parse()
+
+	status := 0
+	for _, g := range goldens {
+		data, changes, err := regen(*dir, g)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "regen: %v\n", err)
+			status = 1
+			continue
+		}
+		fmt.Printf("%s: %d changed stems\n", g.output, changes)
+		if *write && changes > 0 {
+			if err := ioutil.WriteFile(filepath.Join(*dir, g.output), data, 0644); err != nil {
+				fmt.Fprintf(os.Stderr, "regen: %v\n", err)
+				status = 1
+			}
+		}
+	}
+	os.Exit(status)
+}
@@ -0,0 +1,690 @@
+package porter
+
+import (
+	"bufio"
+	_ "embed" // for the default lexicon
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// This file implements the Krovetz stemmer (KStem).  For the algorithm, see:
+//
+// Robert Krovetz, "Viewing morphology as an inference process", Proceedings
+// of the 16th ACM SIGIR Conference, 1993, pp. 191-202.
+//
+// KStem only removes an ending if what is left is in its lexicon, so that
+// its stems are words.  A word that is in the lexicon is not stemmed at all.
+// The steps below follow the order and the candidate forms of the
+// implementation that Krovetz distributed (and that Lucene ports): plurals,
+// past tenses and -ing first, then derivational endings.  When no candidate is
+// in the lexicon, the inflectional steps still remove the ending, and the
+// derivational steps leave the word alone, except for the productive endings
+// (-ness, -ization, -izer, -ability, -ical, -ably, -ally and -ly).
+//
+// Unlike the Porter stemmer, a candidate can be longer than the word (for
+// example, "-ic" is tried as "-ical"), so the stem may be longer than the
+// word.  It then grows into new runes, and the runes after the word are never
+// written.
+
+// kstemLexicon is the default lexicon, made by internal/mklexicon.
+//
+//go:embed kstem_lexicon.txt
+var kstemLexicon string
+
+// kstemIrregular are irregular forms, and their roots.  Forms that are also
+// words in their own right, such as "found", "left" or "saw", are left out.
+var kstemIrregular = map[string]string{
+	// The direct conflations of Krovetz.
+	"aging": "age", "going": "go", "goes": "go", "lying": "lie", "using": "use",
+	"owing": "owe", "suing": "sue", "dying": "die", "tying": "tie", "vying": "vie",
+	"aged": "age", "used": "use", "vied": "vie", "cued": "cue", "died": "die",
+	"eyed": "eye", "hued": "hue", "iced": "ice", "lied": "lie", "owed": "owe",
+	"sued": "sue", "toed": "toe", "tied": "tie", "does": "do", "doing": "do",
+	"aeronautical": "aeronautics", "mathematical": "mathematics",
+	"political": "politics", "metaphysical": "metaphysics",
+	"cylindrical": "cylinder", "nazism": "nazi", "ambiguity": "ambiguous",
+	"barbarity": "barbarous", "credulity": "credulous",
+	"generosity": "generous", "spontaneity": "spontaneous",
+	"unanimity": "unanimous", "voracity": "voracious", "fled": "flee",
+	"miscarriage": "miscarry",
+
+	// Irregular verbs.
+	"am": "be", "are": "be", "is": "be", "was": "be", "were": "be", "been": "be",
+	"has": "have", "had": "have", "having": "have", "did": "do", "done": "do",
+	"went": "go", "gone": "go", "arose": "arise", "arisen": "arise",
+	"awoke": "awake", "awoken": "awake", "beaten": "beat", "became": "become",
+	"began": "begin", "begun": "begin", "bitten": "bite", "bled": "bleed",
+	"blew": "blow", "blown": "blow", "broke": "break", "broken": "break",
+	"bred": "breed", "brought": "bring", "built": "build", "burnt": "burn",
+	"bought": "buy", "caught": "catch", "chose": "choose", "chosen": "choose",
+	"clung": "cling", "came": "come", "crept": "creep", "dealt": "deal",
+	"dug": "dig", "drew": "draw", "drawn": "draw", "dreamt": "dream",
+	"drank": "drink", "drunk": "drink", "drove": "drive", "driven": "drive",
+	"ate": "eat", "eaten": "eat", "fallen": "fall", "fed": "feed", "felt": "feel",
+	"fought": "fight", "flew": "fly", "flown": "fly", "flung": "fling",
+	"forbade": "forbid", "forbidden": "forbid", "forgot": "forget",
+	"forgotten": "forget", "forgave": "forgive", "forgiven": "forgive",
+	"froze": "freeze", "frozen": "freeze", "got": "get", "gotten": "get",
+	"gave": "give", "given": "give", "grew": "grow", "grown": "grow",
+	"hung": "hang", "heard": "hear", "hid": "hide", "hidden": "hide",
+	"held": "hold", "kept": "keep", "knelt": "kneel", "knew": "know",
+	"known": "know", "laid": "lay", "led": "lead", "leapt": "leap",
+	"learnt": "learn", "lent": "lend", "lain": "lie", "lit": "light",
+	"lost": "lose", "made": "make", "meant": "mean", "met": "meet", "paid": "pay",
+	"rode": "ride", "ridden": "ride", "rang": "ring", "rung": "ring",
+	"risen": "rise", "ran": "run", "said": "say", "seen": "see",
+	"sought": "seek", "sold": "sell", "sent": "send", "shook": "shake",
+	"shaken": "shake", "shone": "shine", "shrank": "shrink", "shrunk": "shrink",
+	"sang": "sing", "sung": "sing", "sank": "sink", "sunk": "sink", "sat": "sit",
+	"slept": "sleep", "slid": "slide", "spoken": "speak", "sped": "speed",
+	"spent": "spend", "spun": "spin", "sprang": "spring", "sprung": "spring",
+	"stood": "stand", "stolen": "steal", "stuck": "stick", "stung": "sting",
+	"stank": "stink", "stunk": "stink", "strode": "stride",
+	"stridden": "stride", "struck": "strike", "stricken": "strike",
+	"strung": "string", "strove": "strive", "striven": "strive",
+	"swore": "swear", "sworn": "swear", "swept": "sweep", "swam": "swim",
+	"swum": "swim", "swung": "swing", "took": "take", "taken": "take",
+	"taught": "teach", "tore": "tear", "torn": "tear", "told": "tell",
+	"threw": "throw", "thrown": "throw", "trod": "tread", "trodden": "tread",
+	"understood": "understand", "undertook": "undertake",
+	"undertaken": "undertake", "overcame": "overcome", "woke": "wake",
+	"woken": "wake", "wore": "wear", "worn": "wear", "wove": "weave",
+	"woven": "weave", "wept": "weep", "won": "win", "withdrew": "withdraw",
+	"withdrawn": "withdraw", "wrote": "write", "written": "write",
+	"forsook": "forsake", "forsaken": "forsake", "begot": "beget",
+	"begotten": "beget", "beheld": "behold", "slew": "slay", "slain": "slay",
+	"smote": "smite", "smitten": "smite",
+
+	// Irregular plurals.
+	"men": "man", "women": "woman", "children": "child", "feet": "foot",
+	"teeth": "tooth", "geese": "goose", "mice": "mouse", "lice": "louse",
+	"oxen": "ox", "criteria": "criterion", "phenomena": "phenomenon",
+	"analyses": "analysis", "crises": "crisis", "theses": "thesis",
+	"hypotheses": "hypothesis", "indices": "index", "appendices": "appendix",
+	"matrices": "matrix", "vertices": "vertex", "radii": "radius",
+	"fungi": "fungus", "cacti": "cactus", "alumni": "alumnus",
+	"stimuli": "stimulus", "nuclei": "nucleus", "syllabi": "syllabus",
+	"knives": "knife", "wives": "wife", "lives": "life", "wolves": "wolf",
+	"halves": "half", "selves": "self", "shelves": "shelf", "calves": "calf",
+	"loaves": "loaf", "thieves": "thief", "elves": "elf", "dwarves": "dwarf",
+	"hooves": "hoof", "scarves": "scarf", "wharves": "wharf",
+}
+
+// KStem is a Krovetz stemmer with a lexicon.  It is safe for concurrent use.
+type KStem struct {
+	// lexicon has the words of the lexicon, and the irregular forms.  The
+	// value is the root of an irregular form, and "" for any other word.
+	lexicon map[string]string
+}
+
+// NewKStem returns a KStem stemmer whose lexicon has the words, as well as
+// the built in irregular forms.
+func NewKStem(words []string) *KStem {
+	k := &KStem{lexicon: make(map[string]string, len(words)+len(kstemIrregular))}
+	for form, root := range kstemIrregular {
+		k.lexicon[form] = root
+	}
+	for _, w := range words {
+		k.add(strings.ToLower(w))
+	}
+	return k
+}
+
+// add adds a word to the lexicon, unless it is an irregular form.
+func (k *KStem) add(w string) {
+	if _, ok := k.lexicon[w]; !ok {
+		k.lexicon[w] = ""
+	}
+}
+
+// ReadKStemLexicon reads the lexicon of a KStem stemmer, which also has the
+// built in irregular forms.
+//
+// The format is the one of ReadExceptions: one word per line.  A line with a
+// single word adds it to the lexicon.  A line with two words makes the first
+// an irregular form, with the second as its root, in place of any built in
+// root.  Blank lines, and anything after a '#', are ignored.  For example:
+//
+//	# words
+//	mouse
+//	run
+//
+//	# irregular forms
+//	mice    mouse
+//	ran     run
+func ReadKStemLexicon(r io.Reader) (*KStem, error) {
+	k := NewKStem(nil)
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		switch fields := strings.Fields(line); len(fields) {
+		case 0:
+		case 1:
+			k.add(strings.ToLower(fields[0]))
+		case 2:
+			k.lexicon[strings.ToLower(fields[0])] = strings.ToLower(fields[1])
+		default:
+			return nil, fmt.Errorf("line %d: expected a word, or a word and its root, but got %q", n, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// LoadKStemLexicon reads the lexicon of a KStem stemmer from a file, in the
+// format described by ReadKStemLexicon.
+func LoadKStemLexicon(filename string) (*KStem, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	k, err := ReadKStemLexicon(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return k, nil
+}
+
+var defaultKStem = mustReadKStemLexicon(kstemLexicon)
+
+// mustReadKStemLexicon reads a lexicon that is known to be good.
+func mustReadKStemLexicon(lexicon string) *KStem {
+	k, err := ReadKStemLexicon(strings.NewReader(lexicon))
+	if err != nil {
+		panic(err)
+	}
+	return k
+}
+
+// StemString converts a string to a rune array, then stems the result.
+func (k *KStem) StemString(s string) string {
+	ra := []rune(s)
+	ra = k.Stem(ra)
+	return string(ra)
+}
+
+// Stem converts the runes to lower case, then stems the lowercase runes.
+func (k *KStem) Stem(s []rune) []rune {
+	if len(s) == 0 {
+		return s
+	}
+	for i := 0; i < len(s); i++ {
+		s[i] = unicode.ToLower(s[i])
+	}
+	return k.StemWithoutLowerCasing(s)
+}
+
+// StemWithoutLowerCasing applies the stemming assuming that the runes are
+// lowercase.  Words of one or two letters, and words with anything but the
+// letters a to z, are not stemmed.
+func (k *KStem) StemWithoutLowerCasing(s []rune) []rune {
+	if len(s) <= 2 {
+		return s
+	}
+	for _, r := range s {
+		if r < 'a' || 'z' < r {
+			return s
+		}
+	}
+	w := kstemWord{k: k, s: s[:len(s):len(s)]}
+	if !w.found() {
+		steps := []func(*kstemWord) bool{
+			(*kstemWord).plural,
+			(*kstemWord).pastTense,
+			(*kstemWord).aspect,
+			(*kstemWord).ity,
+			(*kstemWord).ness,
+			(*kstemWord).ion,
+			(*kstemWord).erOr,
+			(*kstemWord).ly,
+			(*kstemWord).al,
+			(*kstemWord).ic,
+			(*kstemWord).nce,
+			(*kstemWord).ble,
+			(*kstemWord).ism,
+			(*kstemWord).ment,
+			(*kstemWord).ize,
+		}
+		for _, step := range steps {
+			if step(&w) {
+				break
+			}
+		}
+	}
+	if root := k.lexicon[string(w.s)]; root != "" {
+		w.set(0, root)
+	}
+	return w.s
+}
+
+// kstemWord is a word being stemmed by KStem.
+type kstemWord struct {
+	k *KStem
+	s []rune
+}
+
+// found returns true if the word is in the lexicon.
+func (w *kstemWord) found() bool {
+	_, ok := w.k.lexicon[string(w.s)]
+	return ok
+}
+
+// set replaces the letters of the word from the j'th on with suffix.
+func (w *kstemWord) set(j int, suffix string) {
+	w.s = w.s[:j]
+	for _, r := range suffix {
+		w.s = append(w.s, r)
+	}
+}
+
+// try replaces the letters of the word from the j'th on with suffix, and
+// returns true if the result is in the lexicon.
+func (w *kstemWord) try(j int, suffix string) bool {
+	w.set(j, suffix)
+	return w.found()
+}
+
+// ends returns the length of the word without suffix, if the word ends with
+// suffix and has at least one letter before it, or -1.
+func (w *kstemWord) ends(suffix string) int {
+	if len(w.s) > len(suffix) && endsWith(w.s, suffix) {
+		return len(w.s) - len(suffix)
+	}
+	return -1
+}
+
+// isConsonant returns true if s[i] is a consonant, as in the Porter
+// algorithm.
+func (w *kstemWord) isConsonant(i int) bool {
+	return isConsonant(w.s, i)
+}
+
+// doubleConsonant returns true if the first j letters end with a double
+// consonant.
+func (w *kstemWord) doubleConsonant(j int) bool {
+	return hasRepeatDoubleConsonantSuffix(w.s[:j])
+}
+
+// plural handles -ies, -es and -s.
+func (w *kstemWord) plural() bool {
+	n := len(w.s)
+	if w.s[n-1] != 's' {
+		return false
+	}
+	if j := w.ends("ies"); j >= 0 {
+		if w.try(j, "ie") {
+			return true
+		}
+		return w.try(j, "y")
+	}
+	if j := w.ends("es"); j >= 0 {
+		// Try removing the -s, unless that leaves -sse.
+		tryE := j > 1 && !(w.s[j-1] == 's' && w.s[j-2] == 's')
+		if tryE && w.try(j, "e") {
+			return true
+		}
+		if w.try(j, "") {
+			return true
+		}
+		// The default is to keep the e.
+		w.set(j, "e")
+		return !tryE && w.found()
+	}
+	if n > 3 && w.s[n-2] != 's' && w.ends("ous") < 0 {
+		return w.try(n-1, "")
+	}
+	return false
+}
+
+// pastTense handles -ied and -ed.
+func (w *kstemWord) pastTense() bool {
+	if len(w.s) <= 4 {
+		return false
+	}
+	if j := w.ends("ied"); j >= 0 {
+		if w.try(j, "ie") {
+			return true
+		}
+		return w.try(j, "y")
+	}
+	// The vowel is needed so that acronyms are not stemmed.
+	if j := w.ends("ed"); j >= 0 && containsVowel(w.s[:j]) {
+		return w.inflection(j, "ed")
+	}
+	return false
+}
+
+// aspect handles -ing.
+func (w *kstemWord) aspect() bool {
+	if len(w.s) <= 5 {
+		return false
+	}
+	if j := w.ends("ing"); j >= 0 && containsVowel(w.s[:j]) {
+		return w.inflection(j, "ing")
+	}
+	return false
+}
+
+// inflection removes -ed or -ing from the word, which is suffix from j on.
+func (w *kstemWord) inflection(j int, suffix string) bool {
+	if w.try(j, "e") || w.try(j, "") {
+		return true
+	}
+	if w.doubleConsonant(j) {
+		c := string(w.s[j-1])
+		if w.try(j-1, "") {
+			return true
+		}
+		// The default is to leave the consonant doubled, e.g.
+		// "fingerspelling" is "fingerspell".
+		w.set(j-1, c)
+		if suffix == "ed" {
+			return w.found()
+		}
+	}
+	if suffix == "ed" {
+		// Leave un- words alone, since un-X-ed is rarely un-X.
+		if w.s[0] == 'u' && w.s[1] == 'n' {
+			w.set(j, suffix)
+			return false
+		}
+	} else if j > 1 && w.isConsonant(j-1) && w.isConsonant(j-2) {
+		return false
+	}
+	// Otherwise, prefer a stem ending with e, e.g. "microcoded" is
+	// "microcode", unless it already does, e.g. "fleeing" is "flee".
+	if w.s[j-1] == 'e' {
+		w.set(j, "")
+		return false
+	}
+	w.set(j, "e")
+	return false
+}
+
+// ity handles -ity.
+func (w *kstemWord) ity() bool {
+	j := w.ends("ity")
+	if j < 0 {
+		return false
+	}
+	if w.try(j, "") || w.try(j, "e") {
+		return true
+	}
+	w.set(j, "ity")
+	switch {
+	case j > 1 && w.s[j-2] == 'i' && w.s[j-1] == 'l':
+		// -ability and -ibility are productive, so always accept them.
+		return w.try(j-2, "le")
+	case j > 1 && w.s[j-2] == 'i' && w.s[j-1] == 'v':
+		return w.try(j, "e")
+	case j > 1 && w.s[j-2] == 'a' && w.s[j-1] == 'l':
+		return w.try(j, "")
+	}
+	return false
+}
+
+// ness handles -ness, which is productive, so it is always removed.
+func (w *kstemWord) ness() bool {
+	j := w.ends("ness")
+	if j < 0 {
+		return false
+	}
+	if w.s[j-1] == 'i' {
+		w.s[j-1] = 'y'
+	}
+	return w.try(j, "")
+}
+
+// ion handles -ization, -ation and -ion.
+func (w *kstemWord) ion() bool {
+	if j := w.ends("ization"); j >= 0 {
+		return w.try(j, "ize")
+	}
+	if j := w.ends("ation"); j >= 0 {
+		if w.try(j, "ate") || w.try(j, "") {
+			return true
+		}
+		w.set(j, "ation")
+	}
+	if j := w.ends("ion"); j >= 0 {
+		if w.try(j, "e") || w.try(j, "") {
+			return true
+		}
+		w.set(j, "ion")
+	}
+	return false
+}
+
+// erOr handles -izer, -er and -or.
+func (w *kstemWord) erOr() bool {
+	if j := w.ends("izer"); j >= 0 {
+		return w.try(j, "ize")
+	}
+	j := w.ends("er")
+	if j < 0 {
+		j = w.ends("or")
+	}
+	if j < 0 {
+		return false
+	}
+	suffix := string(w.s[j:])
+	if w.doubleConsonant(j) {
+		c := string(w.s[j-1])
+		if w.try(j-1, "") {
+			return true
+		}
+		w.set(j-1, c)
+	}
+	if w.s[j-1] == 'i' {
+		if w.try(j-1, "y") {
+			return true
+		}
+		w.set(j-1, "i")
+	}
+	if w.s[j-1] == 'e' {
+		if w.try(j-1, "") {
+			return true
+		}
+		w.set(j-1, "e")
+	}
+	if w.try(j, suffix[:1]) || w.try(j, "") || w.try(j, "e") {
+		return true
+	}
+	w.set(j, suffix)
+	return false
+}
+
+// ly handles -ly.
+func (w *kstemWord) ly() bool {
+	j := w.ends("ly")
+	if j < 0 {
+		return false
+	}
+	if w.try(j, "le") || w.try(j, "") {
+		return true
+	}
+	if j > 1 && w.s[j-2] == 'a' && w.s[j-1] == 'l' {
+		// Always turn -ally into -al.
+		return false
+	}
+	if j > 1 && w.s[j-2] == 'a' && w.s[j-1] == 'b' {
+		// Always turn -ably into -able.
+		return w.try(j, "le")
+	}
+	if w.s[j-1] == 'i' {
+		if w.try(j-1, "y") {
+			return true
+		}
+		w.set(j-1, "i")
+	}
+	// The default is to remove -ly.
+	w.set(j, "")
+	return false
+}
+
+// al handles -al, -ical and -ial.
+func (w *kstemWord) al() bool {
+	if len(w.s) < 4 {
+		return false
+	}
+	j := w.ends("al")
+	if j < 0 {
+		return false
+	}
+	if w.try(j, "") {
+		return true
+	}
+	if w.doubleConsonant(j) {
+		c := string(w.s[j-1])
+		if w.try(j-1, "") {
+			return true
+		}
+		w.set(j-1, c)
+	}
+	if w.try(j, "e") || w.try(j, "um") {
+		return true
+	}
+	w.set(j, "al")
+	if j > 1 && w.s[j-2] == 'i' && w.s[j-1] == 'c' {
+		if w.try(j-2, "") || w.try(j-2, "y") {
+			return true
+		}
+		// The default is to turn -ical into -ic.
+		return w.try(j-2, "ic")
+	}
+	if w.s[j-1] == 'i' {
+		if w.try(j-1, "") {
+			return true
+		}
+		w.set(j-1, "ial")
+	}
+	return false
+}
+
+// ic handles -ic.
+func (w *kstemWord) ic() bool {
+	j := w.ends("ic")
+	if j < 0 {
+		return false
+	}
+	if w.try(j, "ical") || w.try(j, "y") || w.try(j, "e") || w.try(j, "") {
+		return true
+	}
+	w.set(j, "ic")
+	return false
+}
+
+// nce handles -ence and -ance.
+func (w *kstemWord) nce() bool {
+	j := w.ends("nce")
+	if j < 1 || w.s[j-1] != 'e' && w.s[j-1] != 'a' {
+		return false
+	}
+	c := string(w.s[j-1])
+	if w.try(j-1, "e") || w.try(j-1, "") {
+		return true
+	}
+	w.set(j-1, c+"nce")
+	return false
+}
+
+// ble handles -able and -ible.
+func (w *kstemWord) ble() bool {
+	j := w.ends("ble")
+	if j < 1 || w.s[j-1] != 'a' && w.s[j-1] != 'i' {
+		return false
+	}
+	j--
+	suffix := string(w.s[j:])
+	if w.try(j, "") {
+		return true
+	}
+	if w.doubleConsonant(j) {
+		c := string(w.s[j-1])
+		if w.try(j-1, "") {
+			return true
+		}
+		w.set(j-1, c)
+	}
+	if w.try(j, "e") || w.try(j, "ate") {
+		return true
+	}
+	w.set(j, suffix)
+	return false
+}
+
+// ism handles -ism.
+func (w *kstemWord) ism() bool {
+	j := w.ends("ism")
+	if j < 0 {
+		return false
+	}
+	if w.try(j, "") {
+		return true
+	}
+	w.set(j, "ism")
+	return false
+}
+
+// ment handles -ment.
+func (w *kstemWord) ment() bool {
+	j := w.ends("ment")
+	if j < 0 {
+		return false
+	}
+	if w.try(j, "") {
+		return true
+	}
+	w.set(j, "ment")
+	return false
+}
+
+// ize handles -ize.
+func (w *kstemWord) ize() bool {
+	j := w.ends("ize")
+	if j < 0 {
+		return false
+	}
+	if w.try(j, "") {
+		return true
+	}
+	if w.doubleConsonant(j) {
+		c := string(w.s[j-1])
+		if w.try(j-1, "") {
+			return true
+		}
+		w.set(j-1, c)
+	}
+	if w.try(j, "e") {
+		return true
+	}
+	w.set(j, "ize")
+	return false
+}
+
+// KrovetzStemString converts a string to a rune array, then stems the result with
+// the Krovetz algorithm and the default lexicon.
+func KrovetzStemString(s string) string {
+	return defaultKStem.StemString(s)
+}
+
+// KrovetzStem converts the runes to lower case, then stems the lowercase runes
+// with the Krovetz algorithm and the default lexicon.
+func KrovetzStem(s []rune) []rune {
+	return defaultKStem.Stem(s)
+}
+
+// KrovetzStemWithoutLowerCasing applies the Krovetz stemming, with the default
+// lexicon, assuming that the runes are lowercase.
+func KrovetzStemWithoutLowerCasing(s []rune) []rune {
+	return defaultKStem.StemWithoutLowerCasing(s)
+}
@@ -0,0 +1,183 @@
+package porter
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestKrovetzStemString(t *testing.T) {
+	tests := []struct {
+		s, exp string
+	}{
+		{"", ""},
+		{"is", "is"},
+		{"generally", "generally"},
+		{"abilities", "ability"},
+		{"Ponies", "pony"},
+		{"running", "run"},
+		{"shopping", "shop"},
+		{"hoping", "hope"},
+		{"added", "add"},
+		{"microcoded", "microcode"},
+		{"fingerspelling", "fingerspell"},
+		{"fleeing", "flee"},
+		{"readiness", "ready"},
+		{"cardinality", "cardinal"},
+		{"continuance", "continue"},
+		{"nationalization", "national"},
+		{"capacity", "capacity"},
+		{"mice", "mouse"},
+		{"ran", "run"},
+		{"goes", "go"},
+		{"C3PO's", "c3po's"},
+	}
+	for _, test := range tests {
+		if stem := KrovetzStemString(test.s); stem != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.s, stem, test.exp)
+		}
+	}
+}
+
+func TestReadKStemLexicon(t *testing.T) {
+	k, err := ReadKStemLexicon(strings.NewReader(`
+# words
+walk
+Talk   # upper case is folded
+cat
+
+# irregular forms
+kine   cow
+mice   rat   # in place of the built in root
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		s, exp string
+	}{
+		{"walking", "walk"},
+		{"talked", "talk"},
+		{"cats", "cat"},
+		{"kine", "cow"},
+		{"mice", "rat"},
+		{"geese", "goose"},
+		{"jumping", "jump"},
+		{"baking", "bake"},
+	}
+	for _, test := range tests {
+		if stem := k.StemString(test.s); stem != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.s, stem, test.exp)
+		}
+	}
+
+	if _, err := ReadKStemLexicon(strings.NewReader("cat\nmice mouse rodent\n")); err == nil || !strings.HasPrefix(err.Error(), "line 2:") {
+		t.Errorf("ReadKStemLexicon: expected an error on line 2, but got %v", err)
+	}
+}
+
+func TestLoadKStemLexicon(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kstem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "lexicon.txt")
+	if err := ioutil.WriteFile(filename, []byte("news\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	k, err := LoadKStemLexicon(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stem := k.StemString("news"); stem != "news" {
+		t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", "news", stem, "news")
+	}
+	if _, err := LoadKStemLexicon(filepath.Join(dir, "missing.txt")); err == nil {
+		t.Errorf("LoadKStemLexicon did not fail on a missing file")
+	}
+}
+
+// TestKStemWords checks that far more of the stems of KStem than of Porter's
+// are words of its lexicon.  Many words of voc.txt, such as "dcl" or
+// "debhelper", are in no English lexicon, so neither comes near all of them.
+func TestKStemWords(t *testing.T) {
+	voc := getVoc()
+	kstem, porter := 0, 0
+	for _, word := range voc {
+		if _, ok := defaultKStem.lexicon[KrovetzStemString(word)]; ok {
+			kstem++
+		}
+		if _, ok := defaultKStem.lexicon[StemString(word)]; ok {
+			porter++
+		}
+	}
+	if kstem*2 < porter*3 {
+		t.Errorf("%d of KStem's stems are in the lexicon, not half as many again as Porter's %d", kstem, porter)
+	}
+	t.Logf("stems in the lexicon: KStem %d, Porter %d, of %d words", kstem, porter, len(voc))
+}
+
+// TestKStemExamples checks stems that were not made by KrovetzStemString, but
+// worked out by hand from the rules of the paper and the default lexicon.
+func TestKStemExamples(t *testing.T) {
+	fs := readFields(t, "kstem_examples.txt")
+	if len(fs)%2 != 0 {
+		t.Fatalf("kstem_examples.txt has a word without a stem")
+	}
+	for i := 0; i < len(fs); i += 2 {
+		if stem := KrovetzStemString(fs[i]); stem != fs[i+1] {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", fs[i], stem, fs[i+1])
+		}
+	}
+}
+
+// TestKStemSubslice checks that a stem longer than the word does not write
+// over the runes after it.
+func TestKStemSubslice(t *testing.T) {
+	tests := []struct {
+		s, exp string
+	}{
+		{"chose", "choose"},
+		{"mice", "mouse"},
+		{"geese", "goose"},
+		{"zzzic", "zzzic"},
+	}
+	for _, test := range tests {
+		buf := []rune(test.s + "|tail and more")
+		n := len([]rune(test.s))
+		if stem := string(KrovetzStemWithoutLowerCasing(buf[:n])); stem != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.s, stem, test.exp)
+		}
+		if tail := string(buf[n:]); tail != "|tail and more" {
+			t.Errorf("stemming [%s] overwrote the runes after it: [%s]", test.s, tail)
+		}
+	}
+}
+
+func TestKStemVocabulary(t *testing.T) {
+	vs := readFields(t, "voc.txt")
+	os := readFields(t, "kstem_output.txt")
+	if len(vs) != len(os) {
+		t.Fatalf("vocabulary has %d words but output has %d stems", len(vs), len(os))
+	}
+	for i, word := range vs {
+		stem := KrovetzStemString(word)
+		if stem != os[i] {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", word, stem, os[i])
+		}
+	}
+}
+
+func BenchmarkKrovetzString(b *testing.B) {
+	ss := getVoc()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, s := range ss {
+			stem := KrovetzStemString(s)
+			_ = stem
+		}
+	}
+}
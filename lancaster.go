@@ -0,0 +1,391 @@
+package porter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// This file implements the Lancaster (Paice/Husk) stemmer.  For the
+// algorithm, see:
+//
+// Chris D. Paice, "Another stemmer", SIGIR Forum 24(3), 1990, pp. 56-61.
+//
+// The stemmer is iterative: it looks up the rules for the last letter of the
+// word, applies the first one that matches and is acceptable, and then
+// either stops or starts again on the new last letter, as the rule says.
+
+// LancasterRule is a rule of the Lancaster stemmer.  In the rule file format,
+// it is written as the ending reversed, an optional '*' for Intact, the
+// number of letters to remove, the letters to append, and '>' to continue or
+// '.' to stop.  For example, "sei3y>" turns "-ies" into "-y" and continues.
+type LancasterRule struct {
+	// Ending is the ending the word must have, in normal order (e.g.,
+	// "ies").
+	Ending string
+	// Intact is true if the rule only applies to a word that no other rule
+	// has changed yet.
+	Intact bool
+	// Remove is how many letters to remove from the end of the word.
+	Remove int
+	// Append is the letters to append once they are removed.
+	Append string
+	// Continue is true if stemming goes on after the rule is applied.
+	Continue bool
+}
+
+// String writes a rule in the rule file format.
+func (r LancasterRule) String() string {
+	ending := []rune(r.Ending)
+	for i, j := 0, len(ending)-1; i < j; i, j = i+1, j-1 {
+		ending[i], ending[j] = ending[j], ending[i]
+	}
+	rule := string(ending)
+	if r.Intact {
+		rule += "*"
+	}
+	rule += fmt.Sprint(r.Remove) + r.Append
+	if r.Continue {
+		return rule + ">"
+	}
+	return rule + "."
+}
+
+// parseLancasterRule parses a rule in the rule file format.
+func parseLancasterRule(s string) (LancasterRule, error) {
+	var r LancasterRule
+	i := 0
+	for i < len(s) && 'a' <= s[i] && s[i] <= 'z' {
+		i++
+	}
+	if i == 0 {
+		return r, fmt.Errorf("rule %q has no ending", s)
+	}
+	ending := []byte(s[:i])
+	for a, b := 0, len(ending)-1; a < b; a, b = a+1, b-1 {
+		ending[a], ending[b] = ending[b], ending[a]
+	}
+	r.Ending = string(ending)
+	if i < len(s) && s[i] == '*' {
+		r.Intact = true
+		i++
+	}
+	if i == len(s) || s[i] < '0' || s[i] > '9' {
+		return r, fmt.Errorf("rule %q has no count of letters to remove", s)
+	}
+	r.Remove = int(s[i] - '0')
+	i++
+	j := i
+	for j < len(s) && 'a' <= s[j] && s[j] <= 'z' {
+		j++
+	}
+	r.Append = s[i:j]
+	if j != len(s)-1 || (s[j] != '>' && s[j] != '.') {
+		return r, fmt.Errorf("rule %q does not end with '>' or '.'", s)
+	}
+	r.Continue = s[j] == '>'
+	if r.Remove > len(r.Ending) {
+		return r, fmt.Errorf("rule %q removes more letters than its ending has", s)
+	}
+	return r, nil
+}
+
+// Lancaster is a Lancaster stemmer with a set of rules.  It is safe for
+// concurrent use.
+type Lancaster struct {
+	// rules are the rules, by the last letter of their ending, in the order
+	// that they were given.
+	rules map[rune][]LancasterRule
+}
+
+// NewLancaster returns a Lancaster stemmer that uses rules.
+func NewLancaster(rules []LancasterRule) *Lancaster {
+	l := &Lancaster{rules: make(map[rune][]LancasterRule)}
+	for _, r := range rules {
+		if r.Ending == "" {
+			continue
+		}
+		ending := []rune(r.Ending)
+		last := ending[len(ending)-1]
+		l.rules[last] = append(l.rules[last], r)
+	}
+	return l
+}
+
+// ReadLancasterRules reads the rules of a Lancaster stemmer, in the rule file
+// format of Paice and Husk: one rule per line, as described by
+// LancasterRule.  Anything in braces, and anything after the rule on a line,
+// is a comment.  A rule of "end0." ends the rules.  For example:
+//
+//	ai*2.     { -ia > -   if intact }
+//	a*1.      { -a > -    if intact }
+//	bb1.      { -bb > -b }
+//	end0.
+func ReadLancasterRules(r io.Reader) (*Lancaster, error) {
+	var rules []LancasterRule
+	scanner := bufio.NewScanner(r)
+	comment := false
+	for n := 1; scanner.Scan(); n++ {
+		var line strings.Builder
+		for _, c := range scanner.Text() {
+			switch {
+			case c == '{':
+				comment = true
+			case c == '}':
+				comment = false
+			case !comment:
+				line.WriteRune(c)
+			}
+		}
+		fields := strings.Fields(line.String())
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "end0." {
+			break
+		}
+		rule, err := parseLancasterRule(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", n, err)
+		}
+		rules = append(rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return NewLancaster(rules), nil
+}
+
+// LoadLancasterRules reads the rules of a Lancaster stemmer from a file, in
+// the format described by ReadLancasterRules.
+func LoadLancasterRules(filename string) (*Lancaster, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	l, err := ReadLancasterRules(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return l, nil
+}
+
+// isLancasterVowel returns true if the rune is a vowel.  Y counts as one.
+func isLancasterVowel(r rune) bool {
+	switch r {
+	case 'a', 'e', 'i', 'o', 'u', 'y':
+		return true
+	}
+	return false
+}
+
+// lancasterAcceptable returns true if the word would still be acceptable
+// with n letters removed: if it starts with a vowel, at least two letters
+// must be left, and if it starts with a consonant, at least three letters
+// must be left, with a vowel or y as the second or third.
+func lancasterAcceptable(s []rune, n int) bool {
+	if isLancasterVowel(s[0]) {
+		return len(s)-n >= 2
+	}
+	return len(s)-n >= 3 && (isLancasterVowel(s[1]) || isLancasterVowel(s[2]))
+}
+
+// StemString converts a string to a rune array, then stems the result.
+func (l *Lancaster) StemString(s string) string {
+	ra := []rune(s)
+	ra = l.Stem(ra)
+	return string(ra)
+}
+
+// Stem converts the runes to lower case, then stems the lowercase runes.
+func (l *Lancaster) Stem(s []rune) []rune {
+	if len(s) == 0 {
+		return s
+	}
+	for i := 0; i < len(s); i++ {
+		s[i] = unicode.ToLower(s[i])
+	}
+	return l.StemWithoutLowerCasing(s)
+}
+
+// StemWithoutLowerCasing applies the stemming assuming that the runes are
+// lowercase.  A rule may append more letters than it removes, so the stem
+// can be longer than the word, and is then made of new runes.
+func (l *Lancaster) StemWithoutLowerCasing(s []rune) []rune {
+	s = s[:len(s):len(s)]
+	intact := true
+	for len(s) > 0 {
+		applied := false
+		for _, rule := range l.rules[s[len(s)-1]] {
+			if !endsWithString(s, rule.Ending) || rule.Intact && !intact || !lancasterAcceptable(s, rule.Remove) {
+				continue
+			}
+			s = s[:len(s)-rule.Remove]
+			for _, r := range rule.Append {
+				s = append(s, r)
+			}
+			intact = false
+			// A rule that leaves the word as it is stops stemming, even
+			// if it says to continue, so that it cannot loop forever.
+			applied = rule.Continue && (rule.Remove > 0 || rule.Append != "")
+			break
+		}
+		if !applied {
+			break
+		}
+	}
+	return s
+}
+
+// lancasterRules are the canonical rules of Paice and Husk.
+const lancasterRules = `
+ai*2.     { -ia > -   if intact }
+a*1.      { -a > -    if intact }
+bb1.      { -bb > -b }
+city3s.   { -ytic > -ys }
+ci2>      { -ic > - }
+cn1t>     { -nc > -nt }
+dd1.      { -dd > -d }
+dei3y>    { -ied > -y }
+deec2ss.  { -ceed > -cess }
+dee1.     { -eed > -ee }
+de2>      { -ed > - }
+dooh4>    { -hood > - }
+e1>       { -e > - }
+feil1v.   { -lief > -liev }
+fi2>      { -if > - }
+gni3>     { -ing > - }
+gai3y.    { -iag > -y }
+ga2>      { -ag > - }
+gg1.      { -gg > -g }
+ht*2.     { -th > -   if intact }
+hsiug5ct. { -guish > -ct }
+hsi3>     { -ish > - }
+i*1.      { -i > -    if intact }
+i1y>      { -i > -y }
+ji1d.     { -ij > -id   --  see nois4j> & vis3j> }
+juf1s.    { -fuj > -fus }
+ju1d.     { -uj > -ud }
+jo1d.     { -oj > -od }
+jeh1r.    { -hej > -her }
+jrev1t.   { -verj > -vert }
+jsim2t.   { -misj > -mit }
+jn1d.     { -nj > -nd }
+j1s.      { -j > -s }
+lbaifi6.  { -ifiabl > - }
+lbai4y.   { -iabl > -y }
+lba3>     { -abl > - }
+lbi3.     { -ibl > - }
+lib2l>    { -bil > -bl }
+lc1.      { -cl > c }
+lufi4y.   { -iful > -y }
+luf3>     { -ful > - }
+lu2.      { -ul > - }
+lai3>     { -ial > - }
+lau3>     { -ual > - }
+la2>      { -al > - }
+ll1.      { -ll > -l }
+mui3.     { -ium > - }
+mu*2.     { -um > -   if intact }
+msi3>     { -ism > - }
+mm1.      { -mm > -m }
+nois4j>   { -sion > -j }
+noix4ct.  { -xion > -ct }
+noi3>     { -ion > - }
+nai3>     { -ian > - }
+na2>      { -an > - }
+nee0.     { protect  -een }
+ne2>      { -en > - }
+nn1.      { -nn > -n }
+pihs4>    { -ship > - }
+pp1.      { -pp > -p }
+re2>      { -er > - }
+rae0.     { protect  -ear }
+ra2.      { -ar > - }
+ro2>      { -or > - }
+ru2>      { -ur > - }
+rr1.      { -rr > -r }
+rt1>      { -tr > -t }
+rei3y>    { -ier > -y }
+sei3y>    { -ies > -y }
+sis2.     { -sis > -s }
+si2>      { -is > - }
+ssen4>    { -ness > - }
+ss0.      { protect  -ss }
+suo3>     { -ous > - }
+su*2.     { -us > -   if intact }
+s*1>      { -s > -    if intact }
+s0.       { -s > -s }
+tacilp4y. { -plicat > -ply }
+ta2>      { -at > - }
+tnem4>    { -ment > - }
+tne3>     { -ent > - }
+tna3>     { -ant > - }
+tpir2b.   { -ript > -rib }
+tpro2b.   { -orpt > -orb }
+tcud1.    { -duct > -duc }
+tpmus2.   { -sumpt > -sum }
+tpec2iv.  { -cept > -ceiv }
+tulo2v.   { -olut > -olv }
+tsis0.    { protect  -sist }
+tsi3>     { -ist > - }
+tt1.      { -tt > -t }
+uqi3.     { -iqu > - }
+ugo1.     { -ogu > -og }
+vis3j>    { -siv > -j }
+vie0.     { protect  -eiv }
+vi2>      { -iv > - }
+ylb1>     { -bly > -bl }
+yli3y>    { -ily > -y }
+ylp0.     { protect  -ply }
+yl2>      { -ly > - }
+ygo1.     { -ogy > -og }
+yhp1.     { -phy > -ph }
+ymo1.     { -omy > -om }
+ypo1.     { -opy > -op }
+yti3>     { -ity > - }
+yte3>     { -ety > - }
+ytl2.     { -lty > -l }
+yrtsi5.   { -istry > - }
+yra3>     { -ary > - }
+yro3>     { -ory > - }
+yfi3.     { -ify > - }
+ycn2t>    { -ncy > -nt }
+yca3>     { -acy > - }
+zi2>      { -iz > - }
+zy1s.     { -yz > -ys }
+end0.
+`
+
+var defaultLancaster = mustReadLancasterRules(lancasterRules)
+
+// mustReadLancasterRules reads rules that are known to be good.
+func mustReadLancasterRules(rules string) *Lancaster {
+	l, err := ReadLancasterRules(strings.NewReader(rules))
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
+// LancasterStemString converts a string to a rune array, then stems the
+// result with the Lancaster algorithm and its canonical rules.
+func LancasterStemString(s string) string {
+	return defaultLancaster.StemString(s)
+}
+
+// LancasterStem converts the runes to lower case, then stems the lowercase
+// runes with the Lancaster algorithm and its canonical rules.
+func LancasterStem(s []rune) []rune {
+	return defaultLancaster.Stem(s)
+}
+
+// LancasterStemWithoutLowerCasing applies the Lancaster stemming, with its
+// canonical rules, assuming that the runes are lowercase.
+func LancasterStemWithoutLowerCasing(s []rune) []rune {
+	return defaultLancaster.StemWithoutLowerCasing(s)
+}
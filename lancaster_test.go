@@ -0,0 +1,253 @@
+package porter
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLancasterStemString(t *testing.T) {
+	tests := []struct {
+		s, exp string
+	}{
+		{"", ""},
+		{"a", "a"},
+		{"maximum", "maxim"},
+		{"presumably", "presum"},
+		{"multiply", "multiply"},
+		{"provision", "provid"},
+		{"owed", "ow"},
+		{"ear", "ear"},
+		{"saying", "say"},
+		{"crying", "cry"},
+		{"string", "string"},
+		{"meant", "meant"},
+		{"cement", "cem"},
+		{"Maximum", "maxim"},
+		{"happiness", "happy"},
+		{"generalization", "gen"},
+	}
+	for _, test := range tests {
+		if stem := LancasterStemString(test.s); stem != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.s, stem, test.exp)
+		}
+	}
+}
+
+func TestLancasterAcceptable(t *testing.T) {
+	tests := []struct {
+		s   string
+		n   int
+		exp bool
+	}{
+		{"owed", 2, true},
+		{"owed", 3, false},
+		{"ear", 1, true},
+		{"cement", 3, true},
+		{"cement", 4, false},
+		{"string", 3, false},
+		{"crying", 3, true},
+		{"strap", 1, false},
+	}
+	for _, test := range tests {
+		if b := lancasterAcceptable([]rune(test.s), test.n); b != test.exp {
+			t.Errorf("Did NOT get what was expected for calling lancasterAcceptable() on [%s] and %d. Expect [%t] but got [%t]", test.s, test.n, test.exp, b)
+		}
+	}
+}
+
+func TestParseLancasterRule(t *testing.T) {
+	tests := []struct {
+		s   string
+		exp LancasterRule
+	}{
+		{"ai*2.", LancasterRule{Ending: "ia", Intact: true, Remove: 2}},
+		{"sei3y>", LancasterRule{Ending: "ies", Remove: 3, Append: "y", Continue: true}},
+		{"deec2ss.", LancasterRule{Ending: "ceed", Remove: 2, Append: "ss"}},
+		{"nee0.", LancasterRule{Ending: "een"}},
+		{"s*1>", LancasterRule{Ending: "s", Intact: true, Remove: 1, Continue: true}},
+	}
+	for _, test := range tests {
+		r, err := parseLancasterRule(test.s)
+		if err != nil {
+			t.Errorf("parseLancasterRule(%q): %v", test.s, err)
+			continue
+		}
+		if r != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%+v]. Expected: [%+v]", test.s, r, test.exp)
+		}
+		if s := r.String(); s != test.s {
+			t.Errorf("Input: [%+v] -> Actual: [%s]. Expected: [%s]", r, s, test.s)
+		}
+	}
+}
+
+func TestReadLancasterRulesErrors(t *testing.T) {
+	tests := []struct {
+		rules, exp string
+	}{
+		{"ai*2.\n\n2.", "line 3: rule \"2.\" has no ending"},
+		{"ai*.", "line 1: rule \"ai*.\" has no count of letters to remove"},
+		{"{ comment }\nsei3y", "line 2: rule \"sei3y\" does not end with '>' or '.'"},
+		{"sei3y>x", "line 1: rule \"sei3y>x\" does not end with '>' or '.'"},
+		{"s3.", "line 1: rule \"s3.\" removes more letters than its ending has"},
+	}
+	for _, test := range tests {
+		_, err := ReadLancasterRules(strings.NewReader(test.rules))
+		if err == nil || err.Error() != test.exp {
+			t.Errorf("Input: [%q] -> Actual: [%v]. Expected: [%s]", test.rules, err, test.exp)
+		}
+	}
+}
+
+func TestReadLancasterRules(t *testing.T) {
+	// Comments may span lines, and rules after "end0." are ignored.
+	l, err := ReadLancasterRules(strings.NewReader(`
+{ plurals,
+  and nothing else }
+sei3y>    { -ies > -y }
+ss0.      { protect -ss }
+s1>       { -s > - }
+end0.
+gni3>
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		s, exp string
+	}{
+		{"ponies", "pony"},
+		{"glasses", "glasse"},
+		{"glass", "glass"},
+		{"dogs", "dog"},
+		{"running", "running"},
+	}
+	for _, test := range tests {
+		if stem := l.StemString(test.s); stem != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.s, stem, test.exp)
+		}
+	}
+}
+
+func TestLancasterRulesRoundTrip(t *testing.T) {
+	var text strings.Builder
+	for _, r := range []rune("abcdefghijklmnopqrstuvwxyz") {
+		for _, rule := range defaultLancaster.rules[r] {
+			text.WriteString(rule.String() + "\n")
+		}
+	}
+	l, err := ReadLancasterRules(strings.NewReader(text.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, word := range getVoc() {
+		if stem, exp := l.StemString(word), LancasterStemString(word); stem != exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", word, stem, exp)
+		}
+	}
+}
+
+func TestLancasterNoLoop(t *testing.T) {
+	l := NewLancaster([]LancasterRule{{Ending: "s", Continue: true}})
+	if stem := l.StemString("dogs"); stem != "dogs" {
+		t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", "dogs", stem, "dogs")
+	}
+}
+
+func TestLoadLancasterRules(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lancaster")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "rules.txt")
+	if err := ioutil.WriteFile(filename, []byte("gni3>\nde2>\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	l, err := LoadLancasterRules(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stem := l.StemString("jumping"); stem != "jump" {
+		t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", "jumping", stem, "jump")
+	}
+	if _, err := LoadLancasterRules(filepath.Join(dir, "missing.txt")); err == nil {
+		t.Errorf("LoadLancasterRules did not fail on a missing file")
+	}
+}
+
+// TestLancasterNLTK checks the stemmer against the stems that the NLTK
+// LancasterStemmer gives in its documentation.
+func TestLancasterNLTK(t *testing.T) {
+	fs := readFields(t, "lancaster_nltk.txt")
+	if len(fs)%2 != 0 {
+		t.Fatalf("lancaster_nltk.txt has a word without a stem")
+	}
+	for i := 0; i < len(fs); i += 2 {
+		if stem := LancasterStemString(fs[i]); stem != fs[i+1] {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", fs[i], stem, fs[i+1])
+		}
+	}
+}
+
+func TestLancasterGrowingRule(t *testing.T) {
+	rule, err := parseLancasterRule("tp1tion.")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	l := NewLancaster([]LancasterRule{rule})
+	buf := []rune("adopt|tail")
+	if stem := string(l.StemWithoutLowerCasing(buf[:5])); stem != "adoption" {
+		t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", "adopt", stem, "adoption")
+	}
+	if tail := string(buf[5:]); tail != "|tail" {
+		t.Errorf("stemming [adopt] overwrote the runes after it: [%s]", tail)
+	}
+}
+
+func TestLancasterNonASCIIEnding(t *testing.T) {
+	l := NewLancaster([]LancasterRule{
+		{Ending: "ées", Remove: 3},
+		{Ending: "é", Remove: 1, Append: "e"},
+	})
+	tests := []struct {
+		s, exp string
+	}{
+		{"données", "donn"},
+		{"café", "cafe"},
+		{"cafe", "cafe"},
+	}
+	for _, test := range tests {
+		if stem := l.StemString(test.s); stem != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.s, stem, test.exp)
+		}
+	}
+}
+
+func TestLancasterVocabulary(t *testing.T) {
+	vs := readFields(t, "voc.txt")
+	os := readFields(t, "lancaster_output.txt")
+	if len(vs) != len(os) {
+		t.Fatalf("vocabulary has %d words but output has %d stems", len(vs), len(os))
+	}
+	for i, word := range vs {
+		stem := LancasterStemString(word)
+		if stem != os[i] {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", word, stem, os[i])
+		}
+	}
+}
+
+func BenchmarkLancasterString(b *testing.B) {
+	ss := getVoc()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, s := range ss {
+			stem := LancasterStemString(s)
+			_ = stem
+		}
+	}
+}
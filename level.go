@@ -0,0 +1,70 @@
+package porter
+
+import (
+	"fmt"
+)
+
+// Level is the last step of the Porter algorithm that a stemmer applies, so
+// that it can leave out the derivational steps, which merge words such as
+// "university" and "universe".
+type Level int
+
+// The levels.  The zero Level, Full, applies every step.  The others stop
+// after the step they name.
+const (
+	Full Level = iota
+	AfterStep1a
+	AfterStep1b
+	AfterStep1c
+	AfterStep2
+	AfterStep3
+	AfterStep4
+	AfterStep5a
+)
+
+// The presets.
+const (
+	// Light only removes plurals and -ed and -ing: steps 1a to 1c.
+	Light = AfterStep1c
+	// Medium also maps double suffixes to single ones: steps 1a to 3.
+	Medium = AfterStep3
+)
+
+// levelNames are the names of the levels, by Level.
+var levelNames = []string{"full", "1a", "1b", "light", "2", "medium", "4", "5a"}
+
+// ParseLevel returns the level with a name: "light", "medium" or "full", or
+// the name of the last step to apply, from "1a" to "5b".
+func ParseLevel(name string) (Level, error) {
+	switch name {
+	case "1c":
+		return AfterStep1c, nil
+	case "3":
+		return AfterStep3, nil
+	case "5b":
+		return Full, nil
+	}
+	for l, n := range levelNames {
+		if n == name {
+			return Level(l), nil
+		}
+	}
+	return Full, fmt.Errorf("porter: unknown level %q", name)
+}
+
+// String returns the name of the level, which ParseLevel accepts.
+func (l Level) String() string {
+	if l < 0 || int(l) >= len(levelNames) {
+		return fmt.Sprintf("Level(%d)", int(l))
+	}
+	return levelNames[l]
+}
+
+// steps returns the number of steps to apply, from 1 (only step 1a) to 8.
+// Levels that are out of range apply every step.
+func (l Level) steps() int {
+	if l <= Full || l > AfterStep5a {
+		return 8
+	}
+	return int(l)
+}
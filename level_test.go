@@ -0,0 +1,132 @@
+package porter
+
+import (
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name string
+		exp  Level
+	}{
+		{"full", Full},
+		{"5b", Full},
+		{"light", Light},
+		{"1c", Light},
+		{"medium", Medium},
+		{"3", Medium},
+		{"1a", AfterStep1a},
+		{"1b", AfterStep1b},
+		{"2", AfterStep2},
+		{"4", AfterStep4},
+		{"5a", AfterStep5a},
+	}
+	for _, test := range tests {
+		l, err := ParseLevel(test.name)
+		if err != nil || l != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%v, %v]. Expected: [%v]", test.name, l, err, test.exp)
+		}
+		if l2, err := ParseLevel(l.String()); err != nil || l2 != l {
+			t.Errorf("Input: [%s] -> Actual: [%v, %v]. Expected: [%v]", l.String(), l2, err, l)
+		}
+	}
+	if _, err := ParseLevel("heavy"); err == nil {
+		t.Errorf("ParseLevel did not fail on an unknown level")
+	}
+	if s := Level(42).String(); s != "Level(42)" {
+		t.Errorf("Input: [42] -> Actual: [%s]. Expected: [Level(42)]", s)
+	}
+}
+
+func TestLevelUniversity(t *testing.T) {
+	tests := []struct {
+		level                Level
+		university, universe string
+	}{
+		{Light, "universiti", "universe"},
+		{Medium, "universiti", "universe"},
+		{Full, "univers", "univers"},
+	}
+	for _, test := range tests {
+		p := New(Options{Level: test.level})
+		if stem := p.StemString("university"); stem != test.university {
+			t.Errorf("Input: [%s, %v] -> Actual: [%s]. Expected: [%s]", "university", test.level, stem, test.university)
+		}
+		if stem := p.StemString("universe"); stem != test.universe {
+			t.Errorf("Input: [%s, %v] -> Actual: [%s]. Expected: [%s]", "universe", test.level, stem, test.universe)
+		}
+	}
+}
+
+// TestLevelPipeline checks that stopping after a step is the same as applying
+// the step functions up to it.
+func TestLevelPipeline(t *testing.T) {
+	steps := Pipeline{Step1a, Step1b, Step1c, Step2, Step3, Step4, Step5a, Step5b}
+	for l := Full; l <= AfterStep5a; l++ {
+		p := New(Options{Level: l})
+		pipeline := steps[:l.steps()]
+		trace := 0
+		for _, word := range getVoc() {
+			if len(word) <= 2 {
+				continue
+			}
+			stem, exp := p.StemString(word), pipeline.StemString(word)
+			if stem != exp {
+				t.Errorf("Input: [%s, %v] -> Actual: [%s]. Expected: [%s]", word, l, stem, exp)
+			}
+			if trace < 10 {
+				tr := p.StemTrace(word)
+				if tr.Stem != stem || len(tr.Steps) != len(pipeline) {
+					t.Errorf("Input: [%s, %v] -> Actual: [%s in %d steps]. Expected: [%s in %d steps]", word, l, tr.Stem, len(tr.Steps), stem, len(pipeline))
+				}
+				trace++
+			}
+		}
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	p := Pipeline{Step1a, Step1b}
+	tests := []struct {
+		s, exp string
+	}{
+		{"", ""},
+		{"Ponies", "poni"},
+		{"hopping", "hop"},
+		{"happy", "happy"},
+		{"generalizations", "generalization"},
+	}
+	for _, test := range tests {
+		if stem := p.StemString(test.s); stem != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.s, stem, test.exp)
+		}
+	}
+}
+
+func TestLevelVocabulary(t *testing.T) {
+	vs := readFields(t, "voc.txt")
+	for _, test := range []struct {
+		level  Level
+		output string
+	}{
+		{Light, "light_output.txt"},
+		{Medium, "medium_output.txt"},
+		{Full, "output.txt"},
+	} {
+		os := readFields(t, test.output)
+		if len(vs) != len(os) {
+			t.Fatalf("vocabulary has %d words but %s has %d stems", len(vs), test.output, len(os))
+		}
+		p := New(Options{Level: test.level})
+		for i, word := range vs {
+			exp := os[i]
+			if own, ok := ownDepartures[word]; ok && test.level == Full {
+				exp = own
+			}
+			stem := p.StemString(word)
+			if stem != exp {
+				t.Errorf("Input: [%s, %v] -> Actual: [%s]. Expected: [%s]", word, test.level, stem, exp)
+			}
+		}
+	}
+}
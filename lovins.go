@@ -0,0 +1,338 @@
+package porter
+
+import (
+	"strings"
+	"unicode"
+)
+
+// This file implements the Lovins stemmer.  For the algorithm, see:
+//
+// Julie Beth Lovins, "Development of a stemming algorithm", Mechanical
+// Translation and Computational Linguistics 11(1-2), 1968, pp. 22-31.
+//
+// The stemmer removes the longest of its 294 endings whose condition holds
+// for the stem that is left, undoubles a final consonant, and then respells
+// the end of the stem with one of 35 transformations.  Unlike the Porter
+// stemmer, a transformation can make the word up to two letters longer.
+
+// lovinsEnding is an ending, and the letter of the condition (A to CC) that
+// must hold for the stem that is left when it is removed.
+type lovinsEnding struct {
+	ending, condition string
+}
+
+// lovinsEndings are the endings, from the longest to the shortest.
+var lovinsEndings = []lovinsEnding{
+	// 11
+	{"alistically", "B"}, {"arizability", "A"}, {"izationally", "B"},
+	// 10
+	{"antialness", "A"}, {"arisations", "A"}, {"arizations", "A"}, {"entialness", "A"},
+	// 9
+	{"allically", "C"}, {"antaneous", "A"}, {"antiality", "A"}, {"arisation", "A"},
+	{"arization", "A"}, {"ationally", "B"}, {"ativeness", "A"}, {"eableness", "E"},
+	{"entations", "A"}, {"entiality", "A"}, {"entialize", "A"}, {"entiation", "A"},
+	{"ionalness", "A"}, {"istically", "A"}, {"itousness", "A"}, {"izability", "A"},
+	{"izational", "A"},
+	// 8
+	{"ableness", "A"}, {"arizable", "A"}, {"entation", "A"}, {"entially", "A"},
+	{"eousness", "A"}, {"ibleness", "A"}, {"icalness", "A"}, {"ionalism", "A"},
+	{"ionality", "A"}, {"ionalize", "A"}, {"iousness", "A"}, {"izations", "A"},
+	{"lessness", "A"},
+	// 7
+	{"ability", "A"}, {"aically", "A"}, {"alistic", "B"}, {"alities", "A"},
+	{"ariness", "E"}, {"aristic", "A"}, {"arizing", "A"}, {"ateness", "A"},
+	{"atingly", "A"}, {"ational", "B"}, {"atively", "A"}, {"ativism", "A"},
+	{"elihood", "E"}, {"encible", "A"}, {"entally", "A"}, {"entials", "A"},
+	{"entiate", "A"}, {"entness", "A"}, {"fulness", "A"}, {"ibility", "A"},
+	{"icalism", "A"}, {"icalist", "A"}, {"icality", "A"}, {"icalize", "A"},
+	{"ication", "G"}, {"icianry", "A"}, {"ination", "A"}, {"ingness", "A"},
+	{"ionally", "A"}, {"isation", "A"}, {"ishness", "A"}, {"istical", "A"},
+	{"iteness", "A"}, {"iveness", "A"}, {"ivistic", "A"}, {"ivities", "A"},
+	{"ization", "F"}, {"izement", "A"}, {"oidally", "A"}, {"ousness", "A"},
+	// 6
+	{"aceous", "A"}, {"acious", "B"}, {"action", "G"}, {"alness", "A"},
+	{"ancial", "A"}, {"ancies", "A"}, {"ancing", "B"}, {"ariser", "A"},
+	{"arized", "A"}, {"arizer", "A"}, {"atable", "A"}, {"ations", "B"},
+	{"atives", "A"}, {"eature", "Z"}, {"efully", "A"}, {"encies", "A"},
+	{"encing", "A"}, {"ential", "A"}, {"enting", "C"}, {"entist", "A"},
+	{"eously", "A"}, {"ialist", "A"}, {"iality", "A"}, {"ialize", "A"},
+	{"ically", "A"}, {"icance", "A"}, {"icians", "A"}, {"icists", "A"},
+	{"ifully", "A"}, {"ionals", "A"}, {"ionate", "D"}, {"ioning", "A"},
+	{"ionist", "A"}, {"iously", "A"}, {"istics", "A"}, {"izable", "E"},
+	{"lessly", "A"}, {"nesses", "A"}, {"oidism", "A"},
+	// 5
+	{"acies", "A"}, {"acity", "A"}, {"aging", "B"}, {"aical", "A"},
+	{"alist", "A"}, {"alism", "B"}, {"ality", "A"}, {"alize", "A"},
+	{"allic", "BB"}, {"anced", "B"}, {"ances", "B"}, {"antic", "C"},
+	{"arial", "A"}, {"aries", "A"}, {"arily", "A"}, {"arity", "B"},
+	{"arize", "A"}, {"aroid", "A"}, {"ately", "A"}, {"ating", "I"},
+	{"ation", "B"}, {"ative", "A"}, {"ators", "A"}, {"atory", "A"},
+	{"ature", "E"}, {"early", "Y"}, {"ehood", "A"}, {"eless", "A"},
+	{"elity", "A"}, {"ement", "A"}, {"enced", "A"}, {"ences", "A"},
+	{"eness", "E"}, {"ening", "E"}, {"ental", "A"}, {"ented", "C"},
+	{"ently", "A"}, {"fully", "A"}, {"ially", "A"}, {"icant", "A"},
+	{"ician", "A"}, {"icide", "A"}, {"icism", "A"}, {"icist", "A"},
+	{"icity", "A"}, {"idine", "I"}, {"iedly", "A"}, {"ihood", "A"},
+	{"inate", "A"}, {"iness", "A"}, {"ingly", "B"}, {"inism", "J"},
+	{"inity", "CC"}, {"ional", "A"}, {"ioned", "A"}, {"ished", "A"},
+	{"istic", "A"}, {"ities", "A"}, {"itous", "A"}, {"ively", "A"},
+	{"ivity", "A"}, {"izers", "F"}, {"izing", "F"}, {"oidal", "A"},
+	{"oides", "A"}, {"otide", "A"}, {"ously", "A"},
+	// 4
+	{"able", "A"}, {"ably", "A"}, {"ages", "B"}, {"ally", "B"},
+	{"ance", "B"}, {"ancy", "B"}, {"ants", "B"}, {"aric", "A"},
+	{"arly", "K"}, {"ated", "I"}, {"ates", "A"}, {"atic", "B"},
+	{"ator", "A"}, {"ealy", "Y"}, {"edly", "E"}, {"eful", "A"},
+	{"eity", "A"}, {"ence", "A"}, {"ency", "A"}, {"ened", "E"},
+	{"enly", "E"}, {"eous", "A"}, {"hood", "A"}, {"ials", "A"},
+	{"ians", "A"}, {"ible", "A"}, {"ibly", "A"}, {"ical", "A"},
+	{"ides", "L"}, {"iers", "A"}, {"iful", "A"}, {"ines", "M"},
+	{"ings", "N"}, {"ions", "B"}, {"ious", "A"}, {"isms", "B"},
+	{"ists", "A"}, {"itic", "H"}, {"ized", "F"}, {"izer", "F"},
+	{"less", "A"}, {"lily", "A"}, {"ness", "A"}, {"ogen", "A"},
+	{"ward", "A"}, {"wise", "A"}, {"ying", "B"}, {"yish", "A"},
+	// 3
+	{"acy", "A"}, {"age", "B"}, {"aic", "A"}, {"als", "BB"},
+	{"ant", "B"}, {"ars", "O"}, {"ary", "F"}, {"ata", "A"},
+	{"ate", "A"}, {"eal", "Y"}, {"ear", "Y"}, {"ely", "E"},
+	{"ene", "E"}, {"ent", "C"}, {"ery", "E"}, {"ese", "A"},
+	{"ful", "A"}, {"ial", "A"}, {"ian", "A"}, {"ics", "A"},
+	{"ide", "L"}, {"ied", "A"}, {"ier", "A"}, {"ies", "P"},
+	{"ily", "A"}, {"ine", "M"}, {"ing", "N"}, {"ion", "Q"},
+	{"ish", "C"}, {"ism", "B"}, {"ist", "A"}, {"ite", "AA"},
+	{"ity", "A"}, {"ium", "A"}, {"ive", "A"}, {"ize", "F"},
+	{"oid", "A"}, {"one", "R"}, {"ous", "A"},
+	// 2
+	{"ae", "A"}, {"al", "BB"}, {"ar", "X"}, {"as", "B"},
+	{"ed", "E"}, {"en", "F"}, {"es", "E"}, {"ia", "A"},
+	{"ic", "A"}, {"is", "A"}, {"ly", "B"}, {"on", "S"},
+	{"or", "T"}, {"um", "U"}, {"us", "V"}, {"yl", "R"},
+	{"'s", "A"}, {"s'", "A"},
+	// 1
+	{"a", "A"}, {"e", "A"}, {"i", "A"}, {"o", "A"},
+	{"s", "W"}, {"y", "B"},
+}
+
+// lovinsLongestEnding is the length of the longest ending.
+const lovinsLongestEnding = 11
+
+// lovinsConditions are the conditions of the endings, by ending.
+var lovinsConditions = make(map[string]string, len(lovinsEndings))
+
+func init() {
+	for _, e := range lovinsEndings {
+		lovinsConditions[e.ending] = e.condition
+	}
+}
+
+// lovinsEndsWithAny returns true if s ends with one of the suffixes.
+func lovinsEndsWithAny(s []rune, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if endsWith(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// lovinsCondition returns true if a condition holds for a stem.  Every
+// condition also needs the stem to be at least two letters long.
+func lovinsCondition(condition string, stem []rune) bool {
+	n := len(stem)
+	if n < 2 {
+		return false
+	}
+	switch condition {
+	case "A":
+		return true
+	case "B":
+		return n >= 3
+	case "C":
+		return n >= 4
+	case "D":
+		return n >= 5
+	case "E":
+		return !endsWith(stem, "e")
+	case "F":
+		return n >= 3 && !endsWith(stem, "e")
+	case "G":
+		return n >= 3 && endsWith(stem, "f")
+	case "H":
+		return lovinsEndsWithAny(stem, "t", "ll")
+	case "I":
+		return !lovinsEndsWithAny(stem, "o", "e")
+	case "J":
+		return !lovinsEndsWithAny(stem, "a", "e")
+	case "K":
+		return n >= 3 && (lovinsEndsWithAny(stem, "l", "i") || stem[n-1] == 'e' && stem[n-3] == 'u')
+	case "L":
+		return !lovinsEndsWithAny(stem, "u", "x") && (!endsWith(stem, "s") || endsWith(stem, "os"))
+	case "M":
+		return !lovinsEndsWithAny(stem, "a", "c", "e", "m")
+	case "N":
+		// At least four letters after s**, otherwise three.
+		return n >= 3 && (stem[n-3] != 's' || n >= 4)
+	case "O":
+		return lovinsEndsWithAny(stem, "l", "i")
+	case "P":
+		return !endsWith(stem, "c")
+	case "Q":
+		return n >= 3 && !lovinsEndsWithAny(stem, "l", "n")
+	case "R":
+		return lovinsEndsWithAny(stem, "n", "r")
+	case "S":
+		return endsWith(stem, "dr") || endsWith(stem, "t") && !endsWith(stem, "tt")
+	case "T":
+		return endsWith(stem, "s") || endsWith(stem, "t") && !endsWith(stem, "ot")
+	case "U":
+		return lovinsEndsWithAny(stem, "l", "m", "n", "r")
+	case "V":
+		return endsWith(stem, "c")
+	case "W":
+		return !lovinsEndsWithAny(stem, "s", "u")
+	case "X":
+		return lovinsEndsWithAny(stem, "l", "i") || n >= 3 && stem[n-1] == 'e' && stem[n-3] == 'u'
+	case "Y":
+		return endsWith(stem, "in")
+	case "Z":
+		return !endsWith(stem, "f")
+	case "AA":
+		return lovinsEndsWithAny(stem, "d", "f", "ph", "th", "l", "er", "or", "es", "t")
+	case "BB":
+		return n >= 3 && !lovinsEndsWithAny(stem, "met", "ryst")
+	case "CC":
+		return endsWith(stem, "l")
+	}
+	return false
+}
+
+// lovinsRemoveEnding removes the longest ending whose condition holds for the
+// stem that is left.
+func lovinsRemoveEnding(s []rune) []rune {
+	n := lovinsLongestEnding
+	if n > len(s)-2 {
+		n = len(s) - 2
+	}
+	for ; n >= 1; n-- {
+		stem := s[:len(s)-n]
+		if condition, ok := lovinsConditions[string(s[len(s)-n:])]; ok && lovinsCondition(condition, stem) {
+			return stem
+		}
+	}
+	return s
+}
+
+// lovinsUndouble removes one letter of a final bb, dd, gg, ll, mm, nn, pp,
+// rr, ss or tt.
+func lovinsUndouble(s []rune) []rune {
+	n := len(s)
+	if n >= 2 && s[n-1] == s[n-2] && strings.ContainsRune("bdglmnprst", s[n-1]) {
+		return s[:n-1]
+	}
+	return s
+}
+
+// lovinsTransformation respells a suffix of the stem, unless the suffix
+// follows one of the letters of except.
+type lovinsTransformation struct {
+	suffix, replacement, except string
+}
+
+// lovinsTransformations are the transformations 2 to 35 of the paper.  The
+// first, undoubling, is lovinsUndouble.
+var lovinsTransformations = []lovinsTransformation{
+	{"iev", "ief", ""},
+	{"uct", "uc", ""},
+	{"umpt", "um", ""},
+	{"rpt", "rb", ""},
+	{"urs", "ur", ""},
+	{"istr", "ister", ""},
+	{"metr", "meter", ""},
+	{"olv", "olut", ""},
+	{"ul", "l", "aoi"},
+	{"bex", "bic", ""},
+	{"dex", "dic", ""},
+	{"pex", "pic", ""},
+	{"tex", "tic", ""},
+	{"ax", "ac", ""},
+	{"ex", "ec", ""},
+	{"ix", "ic", ""},
+	{"lux", "luc", ""},
+	{"uad", "uas", ""},
+	{"vad", "vas", ""},
+	{"cid", "cis", ""},
+	{"lid", "lis", ""},
+	{"erid", "eris", ""},
+	{"pand", "pans", ""},
+	{"end", "ens", "s"},
+	{"ond", "ons", ""},
+	{"lud", "lus", ""},
+	{"rud", "rus", ""},
+	{"her", "hes", "pt"},
+	{"mit", "mis", ""},
+	{"ent", "ens", "m"},
+	{"ert", "ers", ""},
+	{"et", "es", "n"},
+	{"yt", "ys", ""},
+	{"yz", "ys", ""},
+}
+
+// lovinsRespell applies the transformation with the longest suffix that
+// matches the stem, if it does not follow one of its exceptions.  If the stem
+// grows past the end of s, it grows into new runes.
+func lovinsRespell(s []rune) []rune {
+	var best *lovinsTransformation
+	for i := range lovinsTransformations {
+		t := &lovinsTransformations[i]
+		if endsWith(s, t.suffix) && (best == nil || len(t.suffix) > len(best.suffix)) {
+			best = t
+		}
+	}
+	if best == nil {
+		return s
+	}
+	k := len(s) - len(best.suffix)
+	if k > 0 && strings.ContainsRune(best.except, s[k-1]) {
+		return s
+	}
+	s = s[:k:len(s)]
+	for _, r := range best.replacement {
+		s = append(s, r)
+	}
+	return s
+}
+
+// LovinsStemString converts a string to a rune array, then stems the result
+// with the Lovins algorithm.
+func LovinsStemString(s string) string {
+	ra := []rune(s)
+	ra = LovinsStem(ra)
+	return string(ra)
+}
+
+// LovinsStem converts the runes to lower case, then stems the lowercase runes
+// with the Lovins algorithm.
+func LovinsStem(s []rune) []rune {
+	if len(s) == 0 {
+		return s
+	}
+	for i := 0; i < len(s); i++ {
+		s[i] = unicode.ToLower(s[i])
+	}
+	return LovinsStemWithoutLowerCasing(s)
+}
+
+// LovinsStemWithoutLowerCasing applies the Lovins stemming assuming that the
+// runes are lowercase.
+func LovinsStemWithoutLowerCasing(s []rune) []rune {
+	s = lovinsRemoveEnding(s)
+	s = lovinsUndouble(s)
+	return lovinsRespell(s)
+}
+
+// lovins is the Lovins algorithm as a Stemmer.
+type lovins struct{}
+
+func (lovins) StemString(s string) string             { return LovinsStemString(s) }
+func (lovins) Stem(s []rune) []rune                   { return LovinsStem(s) }
+func (lovins) StemWithoutLowerCasing(s []rune) []rune { return LovinsStemWithoutLowerCasing(s) }
@@ -0,0 +1,197 @@
+package porter
+
+import (
+	"testing"
+)
+
+func TestLovinsTables(t *testing.T) {
+	if len(lovinsEndings) != 294 {
+		t.Errorf("there are %d endings, not 294", len(lovinsEndings))
+	}
+	if len(lovinsConditions) != len(lovinsEndings) {
+		t.Errorf("there are %d different endings, not %d", len(lovinsConditions), len(lovinsEndings))
+	}
+	for i, e := range lovinsEndings {
+		if i > 0 && len(e.ending) > len(lovinsEndings[i-1].ending) {
+			t.Errorf("ending %q is longer than the one before it", e.ending)
+		}
+		holds := false
+		for _, stem := range []string{"xxxxxx", "xxxxxe", "xxxxxf", "xxxxxl", "xxxxin", "xxxxxc", "xxxxxs", "xxxxxt"} {
+			holds = holds || lovinsCondition(e.condition, []rune(stem))
+		}
+		if !holds {
+			t.Errorf("condition %q of ending %q never holds", e.condition, e.ending)
+		}
+	}
+	// With undoubling, there are 35 transformations.
+	if len(lovinsTransformations) != 34 {
+		t.Errorf("there are %d transformations, not 34", len(lovinsTransformations))
+	}
+}
+
+func TestLovinsCondition(t *testing.T) {
+	tests := []struct {
+		condition, stem string
+		exp             bool
+	}{
+		{"A", "x", false},
+		{"A", "xx", true},
+		{"B", "xx", false},
+		{"B", "xxx", true},
+		{"E", "xxe", false},
+		{"G", "xxf", true},
+		{"G", "xxt", false},
+		{"H", "xxll", true},
+		{"H", "xxl", false},
+		{"K", "xuxe", true},
+		{"K", "xaxe", false},
+		{"L", "xxos", true},
+		{"L", "xxas", false},
+		{"N", "sxx", false},
+		{"N", "xsxx", true},
+		{"N", "xxx", true},
+		{"S", "xxdr", true},
+		{"S", "xxtt", false},
+		{"S", "xxt", true},
+		{"T", "xxot", false},
+		{"T", "xxs", true},
+		{"AA", "xxph", true},
+		{"AA", "xxh", false},
+		{"BB", "xmet", false},
+		{"BB", "xryst", false},
+		{"BB", "xxxt", true},
+	}
+	for _, test := range tests {
+		if b := lovinsCondition(test.condition, []rune(test.stem)); b != test.exp {
+			t.Errorf("Did NOT get what was expected for calling lovinsCondition() on [%s] and [%s]. Expect [%t] but got [%t]", test.condition, test.stem, test.exp, b)
+		}
+	}
+}
+
+func TestLovinsRespell(t *testing.T) {
+	tests := []struct {
+		s, exp string
+	}{
+		{"believ", "belief"},
+		{"absorpt", "absorb"},
+		{"dissolv", "dissolut"},
+		{"index", "indic"},
+		{"complex", "complec"},
+		{"consul", "consl"},
+		{"cheval", "cheval"},
+		{"extend", "extens"},
+		{"send", "send"},
+		{"cipher", "cipher"},
+		{"adher", "adhes"},
+		{"element", "element"},
+		{"content", "contens"},
+		{"secret", "secres"},
+		{"magnet", "magnet"},
+	}
+	for _, test := range tests {
+		if s := string(lovinsRespell([]rune(test.s))); s != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.s, s, test.exp)
+		}
+	}
+}
+
+func TestLovinsStemString(t *testing.T) {
+	tests := []struct {
+		s, exp string
+	}{
+		{"", ""},
+		{"a", "a"},
+		{"nationally", "nat"},
+		{"Sitting", "sit"},
+		{"magnesia", "magnes"},
+		{"rubbing", "rub"},
+		{"matrix", "matric"},
+		{"absorption", "absorb"},
+		{"believe", "belief"},
+		{"parametric", "parameter"},
+		{"dissolved", "dissolut"},
+		{"conclusion", "conclus"},
+	}
+	for _, test := range tests {
+		if stem := LovinsStemString(test.s); stem != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.s, stem, test.exp)
+		}
+	}
+}
+
+// TestLovinsConflation checks the pairs of words that the transformations of
+// the paper are there to conflate.  Each stem was worked out by hand from the
+// tables of the paper.
+func TestLovinsConflation(t *testing.T) {
+	tests := []struct {
+		s, t, exp string
+	}{
+		{"absorbing", "absorption", "absorb"},
+		{"believe", "belief", "belief"},
+		{"conclude", "conclusion", "conclus"},
+		{"decided", "decision", "decis"},
+		{"dissolved", "dissolution", "dissolut"},
+		{"extend", "extension", "extens"},
+		{"index", "indices", "indic"},
+		{"matrix", "matrices", "matric"},
+		{"parametric", "parameter", "parameter"},
+		{"register", "registration", "register"},
+		{"resolve", "resolution", "resolut"},
+		{"rubbing", "rub", "rub"},
+		{"sitting", "sit", "sit"},
+	}
+	for _, test := range tests {
+		for _, s := range []string{test.s, test.t} {
+			if stem := LovinsStemString(s); stem != test.exp {
+				t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", s, stem, test.exp)
+			}
+		}
+	}
+}
+
+// TestLovinsGrowSubslice checks that a stem longer than the word does not
+// write over the runes after it.
+func TestLovinsGrowSubslice(t *testing.T) {
+	tests := []struct {
+		s, exp string
+	}{
+		{"resolv", "resolut"},
+		{"registr", "register"},
+		{"metr", "meter"},
+	}
+	for _, test := range tests {
+		buf := []rune(test.s + "|tail")
+		n := len([]rune(test.s))
+		if stem := string(LovinsStemWithoutLowerCasing(buf[:n])); stem != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.s, stem, test.exp)
+		}
+		if tail := string(buf[n:]); tail != "|tail" {
+			t.Errorf("stemming [%s] overwrote the runes after it: [%s]", test.s, tail)
+		}
+	}
+}
+
+func TestLovinsVocabulary(t *testing.T) {
+	vs := readFields(t, "voc.txt")
+	os := readFields(t, "lovins_output.txt")
+	if len(vs) != len(os) {
+		t.Fatalf("vocabulary has %d words but output has %d stems", len(vs), len(os))
+	}
+	for i, word := range vs {
+		stem := LovinsStemString(word)
+		if stem != os[i] {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", word, stem, os[i])
+		}
+	}
+}
+
+func BenchmarkLovinsString(b *testing.B) {
+	ss := getVoc()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, s := range ss {
+			stem := LovinsStemString(s)
+			_ = stem
+		}
+	}
+}
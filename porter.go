@@ -1,63 +1,11 @@
 package porter
 
 import (
+	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
-// isConsonant returns true if the rune represents a constanant.  Y is regarded
-// a constanant if it starts the word, or is followed by a vowel.
-func isConsonant(s []rune, i int) bool {
-	switch s[i] {
-	case 'a', 'e', 'i', 'o', 'u':
-		return false
-	case 'y':
-		if i == 0 {
-			return true
-		} else {
-			return !isConsonant(s, i-1)
-		}
-	default:
-		return true
-	}
-}
-
-func measure(s []rune) uint {
-	if len(s) == 0 {
-		return 0
-	}
-
-	lenS := len(s)
-	m := uint(0)
-
-	// Ignore (potential) consonant sequence at the beginning of word.
-	i := 0
-	for i = 0; i < len(s) && isConsonant(s, i); i++ {
-	}
-	if i == len(s) {
-		return 0
-	}
-
-	// For each pair of a vowel sequence followed by a consonant sequence, increment result.
-Outer:
-	for i < len(s) {
-		for !isConsonant(s, i) {
-			i++
-			if i >= lenS {
-				break Outer
-			}
-		}
-		for isConsonant(s, i) {
-			i++
-			if i >= lenS {
-				m++
-				break Outer
-			}
-		}
-		m++
-	}
-	return m
-}
-
 // hasSuffix checks if a word has a specific suffix
 func hasSuffix(s, suffix []rune) bool {
 	if len(s) <= len(suffix) {
@@ -71,552 +19,432 @@ func hasSuffix(s, suffix []rune) bool {
 	return true
 }
 
-// containsVowel returns true if the string has a vowel
-func containsVowel(s []rune) bool {
-	for i := 0; i < len(s); i++ {
-		if !isConsonant(s, i) {
-			return true
-		}
-	}
-	return false
+// hasSuffixOrIs checks if a word has a specific suffix, or is the suffix.
+func hasSuffixOrIs(s, suffix []rune) bool {
+	return hasSuffix(s, suffix) || string(s) == string(suffix)
 }
 
-func hasRepeatDoubleConsonantSuffix(s []rune) bool {
-	if len(s) < 2 {
-		return false
+func (w *word) step1a() {
+	lenS := len(w.s)
+	if lenS == 0 {
+		return
 	}
-	if s[len(s)-1] == s[len(s)-2] && isConsonant(s, len(s)-1) {
-		return true
+	if w.hasSuffix("sses") {
+		w.truncate(lenS - 2)
+	} else if w.hasSuffix("ies") {
+		w.truncate(lenS - 2)
+	} else if w.hasSuffix("ss") {
+	} else if w.s[lenS-1] == 's' {
+		w.truncate(lenS - 1)
 	}
-	return false
 }
 
-func hasCVCSuffix(s []rune) bool {
-	if len(s) < 3 {
-		return false
+// step1aStrict is step1a as published, where a rule may match the whole word
+// (e.g., "ies" -> "i").
+func (w *word) step1aStrict() {
+	lenS := len(w.s)
+	if lenS == 0 {
+		return
 	}
-	if isConsonant(s, len(s)-3) && !isConsonant(s, len(s)-2) && isConsonant(s, len(s)-1) {
-		return true
+	if endsWith(w.s, "sses") {
+		w.truncate(lenS - 2)
+	} else if endsWith(w.s, "ies") {
+		w.truncate(lenS - 2)
+	} else if endsWith(w.s, "ss") {
+	} else if w.s[lenS-1] == 's' {
+		w.truncate(lenS - 1)
 	}
-	return false
 }
 
-func step1a(s []rune) []rune {
-	if hasSuffix(s, []rune("sses")) {
-		return s[:len(s)-2]
-	} else if hasSuffix(s, []rune("ies")) {
-		return s[:len(s)-2]
-	} else if hasSuffix(s, []rune("ss")) {
-		return s
-	} else if s[len(s)-1] == 's' {
-		return s[:len(s)-1]
+func (w *word) step1b() {
+	lenS := len(w.s)
+	var lenSuffix int
+	if w.hasSuffix("eed") {
+		if w.measure(lenS-3) > 0 {
+			w.truncate(lenS - 1)
+		}
+		return
+	} else if w.hasSuffix("ed") {
+		lenSuffix = 2
+	} else if w.hasSuffix("ing") {
+		lenSuffix = 3
+	} else {
+		return
 	}
-	return s
-}
 
-func step1b(s []rune) []rune {
-	var result []rune = s
-
-	lenS := len(s)
-	if suffix := []rune("eed"); hasSuffix(s, suffix) {
-		subSlice := s[:len(s)-len(suffix)]
-		if measure(subSlice) > 0 {
-			return s[:len(s)-1]
-		}
-	} else if suffix := []rune("ed"); hasSuffix(s, suffix) {
-		subSlice := s[:len(s)-len(suffix)]
-		if containsVowel(subSlice) {
-			if suffix2 := []rune("at"); hasSuffix(subSlice, suffix2) {
-				return s[:len(s)-len(suffix) - -1]
-			} else if suffix2 := []rune("bl"); hasSuffix(subSlice, suffix2) {
-				return s[:lenS-len(suffix) - -1]
-			} else if suffix2 := []rune("iz"); hasSuffix(subSlice, suffix2) {
-				return s[:lenS-len(suffix) - -1]
-			} else if c := subSlice[len(subSlice)-1]; 'l' != c && 's' != c && 'z' != c && hasRepeatDoubleConsonantSuffix(subSlice) {
-				return subSlice[:len(subSlice)-1]
-			} else if c := subSlice[len(subSlice)-1]; 1 == measure(subSlice) && hasCVCSuffix(subSlice) && 'w' != c && 'x' != c && 'y' != c {
-				result = s[:len(s)-len(suffix) - -1]
-				result[len(result)-1] = 'e'
-				return result
-			} else {
-				return subSlice
-			}
-		}
-	} else if suffix := []rune("ing"); hasSuffix(s, suffix) {
-		subSlice := s[:len(s)-len(suffix)]
-
-		if containsVowel(subSlice) {
-			if suffix2 := []rune("at"); hasSuffix(subSlice, suffix2) {
-				result = s[:len(s)-len(suffix) - -1]
-				result[len(result)-1] = 'e'
-				return result
-			} else if suffix2 := []rune("bl"); hasSuffix(subSlice, suffix2) {
-				result = s[:len(s)-len(suffix) - -1]
-				result[len(result)-1] = 'e'
-				return result
-			} else if suffix2 := []rune("iz"); hasSuffix(subSlice, suffix2) {
-				result = s[:len(s)-len(suffix) - -1]
-				result[len(result)-1] = 'e'
-				return result
-			} else if c := subSlice[len(subSlice)-1]; 'l' != c && 's' != c && 'z' != c && hasRepeatDoubleConsonantSuffix(subSlice) {
-				return subSlice[:len(subSlice)-1]
-			} else if c := subSlice[len(subSlice)-1]; 1 == measure(subSlice) && hasCVCSuffix(subSlice) && 'w' != c && 'x' != c && 'y' != c {
-				result = s[:len(s)-len(suffix) - -1]
-				result[len(result)-1] = 'e'
-				return result
-			} else {
-				return subSlice
-			}
-		}
+	k := lenS - lenSuffix
+	if !w.containsVowel(k) {
+		return
+	}
+	w.truncate(k)
+	if w.hasSuffix("at") || w.hasSuffix("bl") || w.hasSuffix("iz") {
+		w.replaceSuffix(0, "e")
+	} else if c := w.s[k-1]; 'l' != c && 's' != c && 'z' != c && w.endsDoubleConsonant(k) {
+		w.truncate(k - 1)
+	} else if 1 == w.measure(k) && w.endsCVCNotWXY(k) {
+		w.replaceSuffix(0, "e")
 	}
-	return result
 }
 
-func step1c(s []rune) []rune {
-	if len(s) < 2 {
-		return s
+func (w *word) step1c() {
+	lenS := len(w.s)
+	if lenS < 2 {
+		return
 	}
-	stem := s
-	if s[len(s)-1] == 'y' && containsVowel(s[:len(s)-1]) {
-		stem[len(s)-1] = 'i'
-	} else if s[len(s)-1] == 'Y' && containsVowel(s[:len(s)-1]) {
-		stem[len(s)-1] = 'I'
+	if w.s[lenS-1] == 'y' && w.containsVowel(lenS-1) {
+		w.set(lenS-1, 'i')
+	} else if w.s[lenS-1] == 'Y' && w.containsVowel(lenS-1) {
+		w.set(lenS-1, 'I')
 	}
-	return stem
 }
 
-func step2(s []rune) []rune {
-
-	lenS := len(s)
-
-	result := s
-
-	if suffix := []rune("ational"); hasSuffix(s, suffix) {
-		if 0 < measure(s[:lenS-len(suffix)]) {
-			result[lenS-5] = 'e'
-			result = result[:lenS-4]
-		}
-	} else if suffix := []rune("tional"); hasSuffix(s, suffix) {
-		if 0 < measure(s[:lenS-len(suffix)]) {
-			result = result[:lenS-2]
-		}
-	} else if suffix := []rune("enci"); hasSuffix(s, suffix) {
-		if 0 < measure(s[:lenS-len(suffix)]) {
-			result[lenS-1] = 'e'
-		}
-	} else if suffix := []rune("anci"); hasSuffix(s, suffix) {
-		if 0 < measure(s[:lenS-len(suffix)]) {
-			result[lenS-1] = 'e'
-		}
-	} else if suffix := []rune("izer"); hasSuffix(s, suffix) {
-		if 0 < measure(s[:lenS-len(suffix)]) {
-			result = s[:lenS-1]
-		}
-	} else if suffix := []rune("bli"); hasSuffix(s, suffix) { // --DEPARTURE--
-		//		} else if suffix := []rune("abli") ; hasSuffix(s, suffix) {
-		if 0 < measure(s[:lenS-len(suffix)]) {
-			result[lenS-1] = 'e'
-		}
-	} else if suffix := []rune("alli"); hasSuffix(s, suffix) {
-		if 0 < measure(s[:lenS-len(suffix)]) {
-			result = s[:lenS-2]
-		}
-	} else if suffix := []rune("entli"); hasSuffix(s, suffix) {
-		if 0 < measure(s[:lenS-len(suffix)]) {
-			result = s[:lenS-2]
-		}
-	} else if suffix := []rune("eli"); hasSuffix(s, suffix) {
-		if 0 < measure(s[:lenS-len(suffix)]) {
-			result = s[:lenS-2]
-		}
-	} else if suffix := []rune("ousli"); hasSuffix(s, suffix) {
-		if 0 < measure(s[:lenS-len(suffix)]) {
-			result = s[:lenS-2]
-		}
-	} else if suffix := []rune("ization"); hasSuffix(s, suffix) {
-		if 0 < measure(s[:lenS-len(suffix)]) {
-			result[lenS-5] = 'e'
-
-			result = s[:lenS-4]
-		}
-	} else if suffix := []rune("ation"); hasSuffix(s, suffix) {
-		if 0 < measure(s[:lenS-len(suffix)]) {
-			result[lenS-3] = 'e'
-
-			result = s[:lenS-2]
-		}
-	} else if suffix := []rune("ator"); hasSuffix(s, suffix) {
-		if 0 < measure(s[:lenS-len(suffix)]) {
-			result[lenS-2] = 'e'
-
-			result = s[:lenS-1]
-		}
-	} else if suffix := []rune("alism"); hasSuffix(s, suffix) {
-		if 0 < measure(s[:lenS-len(suffix)]) {
-			result = s[:lenS-3]
-		}
-	} else if suffix := []rune("iveness"); hasSuffix(s, suffix) {
-		if 0 < measure(s[:lenS-len(suffix)]) {
-			result = s[:lenS-4]
-		}
-	} else if suffix := []rune("fulness"); hasSuffix(s, suffix) {
-		if 0 < measure(s[:lenS-len(suffix)]) {
-			result = s[:lenS-4]
-		}
-	} else if suffix := []rune("ousness"); hasSuffix(s, suffix) {
-		if 0 < measure(s[:lenS-len(suffix)]) {
-			result = s[:lenS-4]
-		}
-	} else if suffix := []rune("aliti"); hasSuffix(s, suffix) {
-		if 0 < measure(s[:lenS-len(suffix)]) {
-			result = s[:lenS-3]
-		}
-	} else if suffix := []rune("iviti"); hasSuffix(s, suffix) {
-		if 0 < measure(s[:lenS-len(suffix)]) {
-			result[lenS-3] = 'e'
+// Step2 applies step 2.  If departures is true, the "bli" and "logi" rules
+// from the C reference are used in place of the published "abli" rule.
+func (w *word) step2(departures bool) {
+	if departures {
+		porterStep2Index.apply(w)
+	} else {
+		porterStep2StrictIndex.apply(w)
+	}
+}
 
-			result = result[:lenS-2]
-		}
-	} else if suffix := []rune("biliti"); hasSuffix(s, suffix) {
-		if 0 < measure(s[:lenS-len(suffix)]) {
-			result[lenS-5] = 'l'
-			result[lenS-4] = 'e'
+func (w *word) step3() {
+	porterStep3Index.apply(w)
+}
 
-			result = result[:lenS-3]
-		}
-	} else if suffix := []rune("logi"); hasSuffix(s, suffix) { // --DEPARTURE--
-		if 0 < measure(s[:lenS-len(suffix)]) {
-			lenTrim := 1
+func (w *word) step4() {
+	porterStep4Index.apply(w)
+}
 
-			result = s[:lenS-lenTrim]
-		}
+func (w *word) step5a() {
+	lenS := len(w.s)
+	if lenS < 1 || w.s[lenS-1] != 'e' {
+		return
 	}
+	k := lenS - 1
+	if m := w.measure(k); 1 < m || 1 == m && !w.endsCVCNotWXY(k) {
+		w.truncate(k)
+	}
+}
 
-	return result
+func (w *word) step5b() {
+	lenS := len(w.s)
+	if lenS > 2 && w.s[lenS-1] == 'l' && w.s[lenS-2] == 'l' && w.measure(lenS-1) > 1 {
+		w.truncate(lenS - 1)
+	}
 }
 
-func step3(s []rune) []rune {
+// The step functions apply one step of the Porter algorithm to lowercase
+// runes, in place, and return the result.  Applied in order, from Step1a to
+// Step5b, they stem a word the same way as StemWithoutLowerCasing, except that
+// they also stem words of one or two letters.  Callers can apply any of them,
+// in any order, e.g. with a Pipeline.
+//
+// Each step works out again which letters are consonants, so stemming a word
+// with the step functions is slower than with a Porter stemmer.
+
+// Step1a removes plurals: -sses, -ies, -ss and -s.
+func Step1a(s []rune) []rune {
+	w := newWord(s)
+	w.step1a()
+	return w.s
+}
 
-	lenS := len(s)
-	result := s
+// Step1aStrict is Step1a as published, where a rule may match the whole word
+// (e.g., "ies" -> "i").
+func Step1aStrict(s []rune) []rune {
+	w := newWord(s)
+	w.step1aStrict()
+	return w.s
+}
 
-	if suffix := []rune("icate"); hasSuffix(s, suffix) {
-		lenSuffix := len(suffix)
+// Step1b removes -eed, -ed and -ing, and tidies up what is left (e.g.,
+// "hoping" -> "hope", "hopping" -> "hop").
+func Step1b(s []rune) []rune {
+	w := newWord(s)
+	w.step1b()
+	return w.s
+}
 
-		if 0 < measure(s[:lenS-lenSuffix]) {
-			result = result[:lenS-3]
-		}
-	} else if suffix := []rune("ative"); hasSuffix(s, suffix) {
-		lenSuffix := len(suffix)
+// Step1c turns a final y into i, if the word has another vowel.
+func Step1c(s []rune) []rune {
+	w := newWord(s)
+	w.step1c()
+	return w.s
+}
 
-		subSlice := s[:lenS-lenSuffix]
+// Step2 maps double suffixes to single ones (e.g., "-ization" -> "-ize"),
+// with the rules of the C reference.
+func Step2(s []rune) []rune {
+	w := newWord(s)
+	w.step2(true)
+	return w.s
+}
 
-		m := measure(subSlice)
+// Step2Strict is Step2 as published: "abli" instead of "bli", and no "logi".
+func Step2Strict(s []rune) []rune {
+	w := newWord(s)
+	w.step2(false)
+	return w.s
+}
 
-		if 0 < m {
-			result = subSlice
-		}
-	} else if suffix := []rune("alize"); hasSuffix(s, suffix) {
-		lenSuffix := len(suffix)
+// Step3 deals with -ic-, -ful, -ness and the like (e.g., "-icate" -> "-ic").
+func Step3(s []rune) []rune {
+	w := newWord(s)
+	w.step3()
+	return w.s
+}
 
-		if 0 < measure(s[:lenS-lenSuffix]) {
-			result = result[:lenS-3]
-		}
-	} else if suffix := []rune("iciti"); hasSuffix(s, suffix) {
-		lenSuffix := len(suffix)
+// Step4 removes derivational suffixes, such as -ance and -ity, from words
+// with m>1.
+func Step4(s []rune) []rune {
+	w := newWord(s)
+	w.step4()
+	return w.s
+}
 
-		if 0 < measure(s[:lenS-lenSuffix]) {
-			result = result[:lenS-3]
-		}
-	} else if suffix := []rune("ical"); hasSuffix(s, suffix) {
-		lenSuffix := len(suffix)
+// Step5a removes a final e.
+func Step5a(s []rune) []rune {
+	w := newWord(s)
+	w.step5a()
+	return w.s
+}
 
-		if 0 < measure(s[:lenS-lenSuffix]) {
-			result = result[:lenS-2]
-		}
-	} else if suffix := []rune("ful"); hasSuffix(s, suffix) {
-		lenSuffix := len(suffix)
+// Step5b turns a final -ll into -l, for words with m>1.
+func Step5b(s []rune) []rune {
+	w := newWord(s)
+	w.step5b()
+	return w.s
+}
 
-		subSlice := s[:lenS-lenSuffix]
+// Pipeline is a Stemmer that applies steps in order, e.g. some of the step
+// functions of the Porter algorithm.  For example, a stemmer that only
+// removes plurals and -ed and -ing:
+//
+//	p := Pipeline{Step1a, Step1b}
+type Pipeline []func(s []rune) []rune
 
-		m := measure(subSlice)
+// StemString converts a string to a rune array, then stems the result.
+func (p Pipeline) StemString(s string) string {
+	return string(p.Stem([]rune(s)))
+}
 
-		if 0 < m {
-			result = subSlice
-		}
-	} else if suffix := []rune("ness"); hasSuffix(s, suffix) {
-		lenSuffix := len(suffix)
+// Stem converts the runes to lower case, then stems the lowercase runes.
+func (p Pipeline) Stem(s []rune) []rune {
+	for i := 0; i < len(s); i++ {
+		s[i] = unicode.ToLower(s[i])
+	}
+	return p.StemWithoutLowerCasing(s)
+}
 
-		subSlice := s[:lenS-lenSuffix]
+// StemWithoutLowerCasing applies the steps assuming that the runes are
+// lowercase.
+func (p Pipeline) StemWithoutLowerCasing(s []rune) []rune {
+	for _, step := range p {
+		s = step(s)
+	}
+	return s
+}
 
-		m := measure(subSlice)
+// StemString converts a string to a rune array, then stems the result.
+func StemString(s string) string {
+	ra := []rune(s)
+	ra = Stem(ra)
+	return string(ra)
+}
 
-		if 0 < m {
-			result = subSlice
-		}
+// Stem converts the runes to lower case, then stems the lowercase runes
+func Stem(s []rune) []rune {
+	if len(s) == 0 {
+		return s
 	}
-
+	for i := 0; i < len(s); i++ {
+		s[i] = unicode.ToLower(s[i])
+	}
+	result := StemWithoutLowerCasing(s)
 	return result
 }
 
-func step4(s []rune) []rune {
-
-	lenS := len(s)
-	result := s
-
-	if suffix := []rune("al"); hasSuffix(s, suffix) {
-		lenSuffix := len(suffix)
-
-		subSlice := s[:lenS-lenSuffix]
-
-		m := measure(subSlice)
-
-		if 1 < m {
-			result = result[:lenS-lenSuffix]
-		}
-	} else if suffix := []rune("ance"); hasSuffix(s, suffix) {
-		lenSuffix := len(suffix)
-
-		subSlice := s[:lenS-lenSuffix]
-
-		m := measure(subSlice)
-
-		if 1 < m {
-			result = result[:lenS-lenSuffix]
-		}
-	} else if suffix := []rune("ence"); hasSuffix(s, suffix) {
-		lenSuffix := len(suffix)
-
-		subSlice := s[:lenS-lenSuffix]
-
-		m := measure(subSlice)
-
-		if 1 < m {
-			result = result[:lenS-lenSuffix]
-		}
-	} else if suffix := []rune("er"); hasSuffix(s, suffix) {
-		lenSuffix := len(suffix)
-
-		subSlice := s[:lenS-lenSuffix]
-
-		m := measure(subSlice)
-
-		if 1 < m {
-			result = subSlice
-		}
-	} else if suffix := []rune("ic"); hasSuffix(s, suffix) {
-		lenSuffix := len(suffix)
-
-		subSlice := s[:lenS-lenSuffix]
-
-		m := measure(subSlice)
-
-		if 1 < m {
-			result = subSlice
-		}
-	} else if suffix := []rune("able"); hasSuffix(s, suffix) {
-		lenSuffix := len(suffix)
-
-		subSlice := s[:lenS-lenSuffix]
-
-		m := measure(subSlice)
-
-		if 1 < m {
-			result = subSlice
-		}
-	} else if suffix := []rune("ible"); hasSuffix(s, suffix) {
-		lenSuffix := len(suffix)
-
-		subSlice := s[:lenS-lenSuffix]
-
-		m := measure(subSlice)
-
-		if 1 < m {
-			result = subSlice
-		}
-	} else if suffix := []rune("ant"); hasSuffix(s, suffix) {
-		lenSuffix := len(suffix)
-
-		subSlice := s[:lenS-lenSuffix]
-
-		m := measure(subSlice)
-
-		if 1 < m {
-			result = subSlice
-		}
-	} else if suffix := []rune("ement"); hasSuffix(s, suffix) {
-		lenSuffix := len(suffix)
-
-		subSlice := s[:lenS-lenSuffix]
-
-		m := measure(subSlice)
-
-		if 1 < m {
-			result = subSlice
-		}
-	} else if suffix := []rune("ment"); hasSuffix(s, suffix) {
-		lenSuffix := len(suffix)
-
-		subSlice := s[:lenS-lenSuffix]
-
-		m := measure(subSlice)
-
-		if 1 < m {
-			result = subSlice
-		}
-	} else if suffix := []rune("ent"); hasSuffix(s, suffix) {
-		lenSuffix := len(suffix)
-
-		subSlice := s[:lenS-lenSuffix]
-
-		m := measure(subSlice)
-
-		if 1 < m {
-			result = subSlice
-		}
-	} else if suffix := []rune("ion"); hasSuffix(s, suffix) {
-		lenSuffix := len(suffix)
-
-		subSlice := s[:lenS-lenSuffix]
-
-		m := measure(subSlice)
-
-		c := subSlice[len(subSlice)-1]
-
-		if 1 < m && ('s' == c || 't' == c) {
-			result = subSlice
-		}
-	} else if suffix := []rune("ou"); hasSuffix(s, suffix) {
-		lenSuffix := len(suffix)
-
-		subSlice := s[:lenS-lenSuffix]
-
-		m := measure(subSlice)
-
-		if 1 < m {
-			result = subSlice
-		}
-	} else if suffix := []rune("ism"); hasSuffix(s, suffix) {
-		lenSuffix := len(suffix)
-
-		subSlice := s[:lenS-lenSuffix]
-
-		m := measure(subSlice)
-
-		if 1 < m {
-			result = subSlice
-		}
-	} else if suffix := []rune("ate"); hasSuffix(s, suffix) {
-		lenSuffix := len(suffix)
-
-		subSlice := s[:lenS-lenSuffix]
-
-		m := measure(subSlice)
-
-		if 1 < m {
-			result = subSlice
-		}
-	} else if suffix := []rune("iti"); hasSuffix(s, suffix) {
-		lenSuffix := len(suffix)
-
-		subSlice := s[:lenS-lenSuffix]
-
-		m := measure(subSlice)
-
-		if 1 < m {
-			result = subSlice
-		}
-	} else if suffix := []rune("ous"); hasSuffix(s, suffix) {
-		lenSuffix := len(suffix)
-
-		subSlice := s[:lenS-lenSuffix]
-
-		m := measure(subSlice)
-
-		if 1 < m {
-			result = subSlice
-		}
-	} else if suffix := []rune("ive"); hasSuffix(s, suffix) {
-		lenSuffix := len(suffix)
-
-		subSlice := s[:lenS-lenSuffix]
+// StemWithoutLowerCasing applies the stemming assuming that the runes are
+// lowercase.
+func StemWithoutLowerCasing(s []rune) []rune {
+	return defaultPorter.StemWithoutLowerCasing(s)
+}
 
-		m := measure(subSlice)
+// Options configures a Porter stemmer.
+type Options struct {
+	// Strict follows the 1980 paper literally, rather than the C reference
+	// implementation.  The C reference departs from the paper in three
+	// places: words of one or two letters are never stemmed, step 2 matches
+	// "bli" instead of "abli", and step 2 has an extra "logi" rule.  The
+	// default rules also depart from both in a fourth place of their own: a
+	// step 1a suffix is never the whole word, so "ies" stays "ie" rather than
+	// becoming "i".
+	Strict bool
+
+	// Exceptions maps words to fixed stems, which are used instead of
+	// stemming the word.
+	Exceptions map[string]string
+
+	// Protected words are lower cased, but are otherwise never stemmed.
+	Protected map[string]bool
+
+	// Level is the last step to apply.  The zero Level, Full, applies them
+	// all.
+	Level Level
+
+	// MaxIterations is the most times a word is stemmed.  If it is more
+	// than one, the stem is stemmed again until it stops changing, so that
+	// stemming a stem gives back the same stem, or until the word has been
+	// stemmed MaxIterations times.  Zero or one stems a word once, as the
+	// algorithm does.
+	MaxIterations int
+}
 
-		if 1 < m {
-			result = subSlice
-		}
-	} else if suffix := []rune("ize"); hasSuffix(s, suffix) {
-		lenSuffix := len(suffix)
+// FixpointIterations is a bound for Options.MaxIterations that is enough
+// for every word of the test vocabulary to reach its fixpoint.
+const FixpointIterations = 8
 
-		subSlice := s[:lenS-lenSuffix]
+// Porter is a configurable Porter stemmer.  The zero value uses the C
+// reference rules, the same as StemString, Stem and StemWithoutLowerCasing.
+//
+// A Porter is safe for concurrent use.
+type Porter struct {
+	opts Options
+}
 
-		m := measure(subSlice)
+var defaultPorter = &Porter{}
 
-		if 1 < m {
-			result = subSlice
+// New returns a Porter stemmer configured with opts.  The exceptions and
+// protected words are copied, so changing opts afterwards has no effect.
+func New(opts Options) *Porter {
+	exceptions := make(map[string]string, len(opts.Exceptions))
+	for word, stem := range opts.Exceptions {
+		exceptions[strings.ToLower(word)] = stem
+	}
+	protected := make(map[string]bool, len(opts.Protected))
+	for word, ok := range opts.Protected {
+		if ok {
+			protected[strings.ToLower(word)] = true
 		}
 	}
-
-	return result
+	opts.Exceptions, opts.Protected = exceptions, protected
+	return &Porter{opts: opts}
 }
 
-func step5a(s []rune) []rune {
-	if len(s) < 1 {
-		return s
+// lookup returns the fixed stem of a word if it is an exception or is
+// protected.  An exception's stem is written over the word if it fits, and is
+// otherwise returned in new runes, so that the runes after the word are never
+// written.
+func (p *Porter) lookup(s []rune) ([]rune, bool) {
+	if len(p.opts.Exceptions) == 0 && len(p.opts.Protected) == 0 {
+		return s, false
 	}
-	if s[len(s)-1] == 'e' {
-		subSlice := s[:len(s)-1]
-		m := measure(subSlice)
-		if 1 < m {
-			return subSlice
-		} else if 1 == m {
-			if c := subSlice[len(subSlice)-1]; !(hasCVCSuffix(subSlice) && 'w' != c && 'x' != c && 'y' != c) {
-				return subSlice
-			}
-		}
+	word := string(s)
+	if p.opts.Protected[word] {
+		return s, true
 	}
-	return s
-}
-
-func step5b(s []rune) []rune {
-	if len(s) > 2 && s[len(s)-1] == 'l' && s[len(s)-2] == 'l' && measure(s[:len(s)-1]) > 1 {
-		return s[:len(s)-1]
+	if stem, ok := p.opts.Exceptions[word]; ok {
+		if utf8.RuneCountInString(stem) > len(s) {
+			return []rune(stem), true
+		}
+		n := 0
+		for _, r := range stem {
+			s[n] = r
+			n++
+		}
+		return s[:n], true
 	}
-	return s
+	return s, false
 }
 
 // StemString converts a string to a rune array, then stems the result.
-func StemString(s string) string {
+func (p *Porter) StemString(s string) string {
 	ra := []rune(s)
-	ra = Stem(ra)
+	ra = p.Stem(ra)
 	return string(ra)
 }
 
 // Stem converts the runes to lower case, then stems the lowercase runes
-func Stem(s []rune) []rune {
+func (p *Porter) Stem(s []rune) []rune {
 	if len(s) == 0 {
 		return s
 	}
 	for i := 0; i < len(s); i++ {
 		s[i] = unicode.ToLower(s[i])
 	}
-	result := StemWithoutLowerCasing(s)
-	return result
+	return p.StemWithoutLowerCasing(s)
 }
 
 // StemWithoutLowerCasing applies the stemming assuming that the runes are
 // lowercase.
-func StemWithoutLowerCasing(s []rune) []rune {
-	if len(s) <= 2 {
-		return s
+func (p *Porter) StemWithoutLowerCasing(s []rune) []rune {
+	if stem, ok := p.lookup(s); ok {
+		return stem
+	}
+	w := newWord(s)
+	p.stem(&w)
+	if p.opts.MaxIterations > 1 {
+		// The stem of a short word fits in buf, so that comparing it with
+		// the stem of the stem does not allocate.
+		var buf [32]rune
+		for i := 1; i < p.opts.MaxIterations; i++ {
+			prev := append(buf[:0], w.s...)
+			p.stem(&w)
+			if equalRunes(prev, w.s) {
+				break
+			}
+		}
+	}
+	return w.s
+}
+
+// stem applies the steps to a word.
+func (p *Porter) stem(w *word) {
+	if p.opts.Strict {
+		if len(w.s) == 0 {
+			return
+		}
+		w.step1aStrict()
+	} else {
+		if len(w.s) <= 2 { // --DEPARTURE--
+			return
+		}
+		w.step1a()
+	}
+	n := p.opts.Level.steps()
+	if n > 1 {
+		w.step1b()
 	}
-	s = step1a(s)
-	s = step1b(s)
-	s = step1c(s)
-	s = step2(s)
-	s = step3(s)
-	s = step4(s)
-	s = step5a(s)
-	return step5b(s)
+	if n > 2 {
+		w.step1c()
+	}
+	if n > 3 {
+		w.step2(!p.opts.Strict)
+	}
+	if n > 4 {
+		w.step3()
+	}
+	if n > 5 {
+		w.step4()
+	}
+	if n > 6 {
+		w.step5a()
+	}
+	if n > 7 {
+		w.step5b()
+	}
+}
+
+// equalRunes reports whether a and b are the same runes.
+func equalRunes(a, b []rune) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
 }
@@ -0,0 +1,418 @@
+package porter
+
+import (
+	"unicode"
+)
+
+// This file implements the Porter2 ("English") stemmer from the Snowball
+// project.  For the algorithm, see:
+//
+// http://snowball.tartarus.org/algorithms/english/stemmer.html
+//
+// Like the Porter stemmer, it works on the []rune it is given, shrinking and
+// rewriting the word in place.  No rule ever makes the word longer than it was
+// when it was passed in.
+
+// porter2Exceptions1 are words which are given a fixed stem before any other
+// processing.
+var porter2Exceptions1 = map[string]string{
+	"skis":   "ski",
+	"skies":  "sky",
+	"dying":  "die",
+	"lying":  "lie",
+	"tying":  "tie",
+	"idly":   "idl",
+	"gently": "gentl",
+	"ugly":   "ugli",
+	"early":  "earli",
+	"only":   "onli",
+	"singly": "singl",
+	"sky":    "sky",
+	"news":   "news",
+	"howe":   "howe",
+	"atlas":  "atlas",
+	"cosmos": "cosmos",
+	"bias":   "bias",
+	"andes":  "andes",
+}
+
+// porter2Exceptions2 are words which are left alone once step 1a has been
+// applied.
+var porter2Exceptions2 = map[string]bool{
+	"inning":  true,
+	"outing":  true,
+	"canning": true,
+	"herring": true,
+	"earring": true,
+	"proceed": true,
+	"exceed":  true,
+	"succeed": true,
+}
+
+// porter2Prefixes are the prefixes after which R1 starts, instead of after the
+// first non-vowel following a vowel.
+var porter2Prefixes = []string{"gener", "commun", "arsen"}
+
+// porter2Rule is a suffix, and what it is replaced with.
+type porter2Rule struct {
+	suffix, replacement string
+}
+
+// The rules for steps 2, 3 and 4, longest suffix first, since only the longest
+// matching suffix is ever considered.
+var (
+	porter2Step2Rules = []porter2Rule{
+		{"ational", "ate"},
+		{"iveness", "ive"},
+		{"fulness", "ful"},
+		{"ousness", "ous"},
+		{"ization", "ize"},
+		{"tional", "tion"},
+		{"biliti", "ble"},
+		{"lessli", "less"},
+		{"entli", "ent"},
+		{"ousli", "ous"},
+		{"fulli", "ful"},
+		{"aliti", "al"},
+		{"iviti", "ive"},
+		{"alism", "al"},
+		{"ation", "ate"},
+		{"enci", "ence"},
+		{"anci", "ance"},
+		{"abli", "able"},
+		{"izer", "ize"},
+		{"ator", "ate"},
+		{"alli", "al"},
+		{"bli", "ble"},
+		{"ogi", "og"},
+		{"li", ""},
+	}
+	porter2Step3Rules = []porter2Rule{
+		{"ational", "ate"},
+		{"tional", "tion"},
+		{"alize", "al"},
+		{"icate", "ic"},
+		{"iciti", "ic"},
+		{"ative", ""},
+		{"ical", "ic"},
+		{"ness", ""},
+		{"ful", ""},
+	}
+	porter2Step4Suffixes = []string{
+		"ement",
+		"ance", "ence", "able", "ible", "ment",
+		"ant", "ent", "ism", "ate", "iti", "ous", "ive", "ize", "ion",
+		"al", "er", "ic",
+	}
+)
+
+// endsWith checks if a word ends with a suffix.  Unlike hasSuffix, the suffix
+// may be the whole word.
+func endsWith(s []rune, suffix string) bool {
+	i := len(s)
+	for j := len(suffix) - 1; j >= 0; j-- {
+		i--
+		if i < 0 || s[i] != rune(suffix[j]) {
+			return false
+		}
+	}
+	return true
+}
+
+// replaceSuffix replaces the last n runes of s with replacement.  If the
+// replacement is longer than n, it is appended to s, so callers make sure
+// that the word as a whole never grows.
+func replaceSuffix(s []rune, n int, replacement string) []rune {
+	s = s[:len(s)-n]
+	for _, r := range replacement {
+		s = append(s, r)
+	}
+	return s
+}
+
+// isPorter2Vowel returns true if the rune is a vowel.  Y is a vowel, unless it
+// has been marked as a consonant by changing it to upper case.
+func isPorter2Vowel(r rune) bool {
+	switch r {
+	case 'a', 'e', 'i', 'o', 'u', 'y':
+		return true
+	}
+	return false
+}
+
+// porter2ContainsVowel returns true if the runes contain a vowel.
+func porter2ContainsVowel(s []rune) bool {
+	for _, r := range s {
+		if isPorter2Vowel(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// porter2RegionStart returns the index after the first non-vowel following a
+// vowel, at or after index i.  It returns len(s) if there is none.
+func porter2RegionStart(s []rune, i int) int {
+	for ; i < len(s) && !isPorter2Vowel(s[i]); i++ {
+	}
+	for ; i < len(s) && isPorter2Vowel(s[i]); i++ {
+	}
+	if i >= len(s) {
+		return len(s)
+	}
+	return i + 1
+}
+
+// porter2Regions returns the start of the R1 and R2 regions.
+func porter2Regions(s []rune) (r1, r2 int) {
+	r1 = -1
+	for _, prefix := range porter2Prefixes {
+		if len(s) >= len(prefix) && endsWith(s[:len(prefix)], prefix) {
+			r1 = len(prefix)
+			break
+		}
+	}
+	if r1 < 0 {
+		r1 = porter2RegionStart(s, 0)
+	}
+	return r1, porter2RegionStart(s, r1)
+}
+
+// porter2ShortSyllable returns true if s[:end] ends with a short syllable.
+// That is either a non-vowel, a vowel, and a non-vowel other than w, x or Y;
+// or a vowel at the beginning of the word followed by a non-vowel.
+func porter2ShortSyllable(s []rune, end int) bool {
+	if end >= 3 && !isPorter2Vowel(s[end-3]) && isPorter2Vowel(s[end-2]) && !isPorter2Vowel(s[end-1]) {
+		switch s[end-1] {
+		case 'w', 'x', 'Y':
+		default:
+			return true
+		}
+	}
+	return end == 2 && isPorter2Vowel(s[0]) && !isPorter2Vowel(s[1])
+}
+
+// porter2Prelude removes an initial apostrophe, and marks each y that is a
+// consonant by changing it to Y.
+func porter2Prelude(s []rune) ([]rune, bool) {
+	if len(s) > 0 && s[0] == '\'' {
+		s = s[1:]
+	}
+	yFound := false
+	for i := 0; i < len(s); i++ {
+		if s[i] == 'y' && (i == 0 || isPorter2Vowel(s[i-1])) {
+			s[i] = 'Y'
+			yFound = true
+		}
+	}
+	return s, yFound
+}
+
+func porter2Step1a(s []rune) []rune {
+	for _, suffix := range []string{"'s'", "'s", "'"} {
+		if endsWith(s, suffix) {
+			s = s[:len(s)-len(suffix)]
+			break
+		}
+	}
+	switch {
+	case endsWith(s, "sses"):
+		return s[:len(s)-2]
+	case endsWith(s, "ied"), endsWith(s, "ies"):
+		if len(s) > 4 {
+			return replaceSuffix(s, 3, "i")
+		}
+		return replaceSuffix(s, 3, "ie")
+	case endsWith(s, "ss"), endsWith(s, "us"):
+		return s
+	case endsWith(s, "s"):
+		if len(s) > 2 && porter2ContainsVowel(s[:len(s)-2]) {
+			return s[:len(s)-1]
+		}
+	}
+	return s
+}
+
+func porter2Step1b(s []rune, r1 int) []rune {
+	var suffix string
+	for _, sfx := range []string{"eedly", "ingly", "edly", "eed", "ing", "ed"} {
+		if endsWith(s, sfx) {
+			suffix = sfx
+			break
+		}
+	}
+	switch suffix {
+	case "":
+		return s
+	case "eed", "eedly":
+		if len(s)-len(suffix) >= r1 {
+			return replaceSuffix(s, len(suffix), "ee")
+		}
+		return s
+	}
+	stem := s[:len(s)-len(suffix)]
+	if !porter2ContainsVowel(stem) {
+		return s
+	}
+	switch {
+	case endsWith(stem, "at"), endsWith(stem, "bl"), endsWith(stem, "iz"):
+		return replaceSuffix(s, len(suffix), "e")
+	case len(stem) >= 2 && stem[len(stem)-1] == stem[len(stem)-2]:
+		switch stem[len(stem)-1] {
+		case 'b', 'd', 'f', 'g', 'm', 'n', 'p', 'r', 't':
+			return stem[:len(stem)-1]
+		}
+	}
+	if len(stem) == r1 && porter2ShortSyllable(stem, len(stem)) {
+		return replaceSuffix(s, len(suffix), "e")
+	}
+	return stem
+}
+
+func porter2Step1c(s []rune) []rune {
+	if len(s) < 3 {
+		return s
+	}
+	if c := s[len(s)-1]; (c == 'y' || c == 'Y') && !isPorter2Vowel(s[len(s)-2]) {
+		s[len(s)-1] = 'i'
+	}
+	return s
+}
+
+func porter2Step2(s []rune, r1 int) []rune {
+	for _, rule := range porter2Step2Rules {
+		if !endsWith(s, rule.suffix) {
+			continue
+		}
+		stemLen := len(s) - len(rule.suffix)
+		if stemLen < r1 {
+			return s
+		}
+		switch rule.suffix {
+		case "ogi":
+			if stemLen == 0 || s[stemLen-1] != 'l' {
+				return s
+			}
+		case "li":
+			if stemLen == 0 {
+				return s
+			}
+			switch s[stemLen-1] {
+			case 'c', 'd', 'e', 'g', 'h', 'k', 'm', 'n', 'r', 't':
+			default:
+				return s
+			}
+		}
+		return replaceSuffix(s, len(rule.suffix), rule.replacement)
+	}
+	return s
+}
+
+func porter2Step3(s []rune, r1, r2 int) []rune {
+	for _, rule := range porter2Step3Rules {
+		if !endsWith(s, rule.suffix) {
+			continue
+		}
+		stemLen := len(s) - len(rule.suffix)
+		if stemLen < r1 || (rule.suffix == "ative" && stemLen < r2) {
+			return s
+		}
+		return replaceSuffix(s, len(rule.suffix), rule.replacement)
+	}
+	return s
+}
+
+func porter2Step4(s []rune, r2 int) []rune {
+	for _, suffix := range porter2Step4Suffixes {
+		if !endsWith(s, suffix) {
+			continue
+		}
+		stemLen := len(s) - len(suffix)
+		if stemLen < r2 {
+			return s
+		}
+		if suffix == "ion" && (stemLen == 0 || (s[stemLen-1] != 's' && s[stemLen-1] != 't')) {
+			return s
+		}
+		return s[:stemLen]
+	}
+	return s
+}
+
+func porter2Step5(s []rune, r1, r2 int) []rune {
+	if len(s) == 0 {
+		return s
+	}
+	stemLen := len(s) - 1
+	switch s[stemLen] {
+	case 'e':
+		if stemLen >= r2 || (stemLen >= r1 && !porter2ShortSyllable(s, stemLen)) {
+			return s[:stemLen]
+		}
+	case 'l':
+		if stemLen >= r2 && stemLen > 0 && s[stemLen-1] == 'l' {
+			return s[:stemLen]
+		}
+	}
+	return s
+}
+
+// Porter2StemString converts a string to a rune array, then stems the result
+// with the Porter2 algorithm.
+func Porter2StemString(s string) string {
+	ra := []rune(s)
+	ra = Porter2Stem(ra)
+	return string(ra)
+}
+
+// Porter2Stem converts the runes to lower case, then stems the lowercase runes
+// with the Porter2 algorithm.
+func Porter2Stem(s []rune) []rune {
+	if len(s) == 0 {
+		return s
+	}
+	for i := 0; i < len(s); i++ {
+		s[i] = unicode.ToLower(s[i])
+	}
+	return Porter2StemWithoutLowerCasing(s)
+}
+
+// Porter2StemWithoutLowerCasing applies the Porter2 stemming assuming that the
+// runes are lowercase.
+func Porter2StemWithoutLowerCasing(s []rune) []rune {
+	if stem, ok := porter2Exceptions1[string(s)]; ok {
+		return replaceSuffix(s, len(s), stem)
+	}
+	if len(s) < 3 {
+		return s
+	}
+
+	s, yFound := porter2Prelude(s)
+	r1, r2 := porter2Regions(s)
+
+	s = porter2Step1a(s)
+	if !porter2Exceptions2[string(s)] {
+		s = porter2Step1b(s, r1)
+		s = porter2Step1c(s)
+		s = porter2Step2(s, r1)
+		s = porter2Step3(s, r1, r2)
+		s = porter2Step4(s, r2)
+		s = porter2Step5(s, r1, r2)
+	}
+
+	if yFound {
+		for i := 0; i < len(s); i++ {
+			if s[i] == 'Y' {
+				s[i] = 'y'
+			}
+		}
+	}
+	return s
+}
+
+// porter2 is the Porter2 algorithm as a Stemmer.
+type porter2 struct{}
+
+func (porter2) StemString(s string) string             { return Porter2StemString(s) }
+func (porter2) Stem(s []rune) []rune                   { return Porter2Stem(s) }
+func (porter2) StemWithoutLowerCasing(s []rune) []rune { return Porter2StemWithoutLowerCasing(s) }
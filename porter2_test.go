@@ -0,0 +1,115 @@
+package porter
+
+import (
+	"testing"
+)
+
+func TestPorter2Regions(t *testing.T) {
+	tests := []struct {
+		s      string
+		r1, r2 string
+	}{
+		{"beautiful", "iful", "ul"},
+		{"beauty", "y", ""},
+		{"beau", "", ""},
+		{"animadversion", "imadversion", "adversion"},
+		{"sprinkled", "kled", ""},
+		{"eucharist", "harist", "ist"},
+		{"generous", "ous", ""},
+		{"communism", "ism", "m"},
+		{"arsenal", "al", ""},
+	}
+	for _, test := range tests {
+		s := []rune(test.s)
+		r1, r2 := porter2Regions(s)
+		if string(s[r1:]) != test.r1 || string(s[r2:]) != test.r2 {
+			t.Errorf("Did NOT get what was expected for calling porter2Regions() on [%s]. Expect R1 [%s] and R2 [%s] but got [%s] and [%s]", test.s, test.r1, test.r2, string(s[r1:]), string(s[r2:]))
+		}
+	}
+}
+
+func TestPorter2ShortSyllable(t *testing.T) {
+	tests := []struct {
+		s   string
+		exp bool
+	}{
+		{"rap", true},
+		{"trap", true},
+		{"entrap", true},
+		{"ow", true},
+		{"on", true},
+		{"at", true},
+		{"uproot", false},
+		{"bestow", false},
+		{"disturb", false},
+		{"fax", false},
+		{"plaY", false},
+	}
+	for _, test := range tests {
+		s := []rune(test.s)
+		if b := porter2ShortSyllable(s, len(s)); b != test.exp {
+			t.Errorf("Did NOT get what was expected for calling porter2ShortSyllable() on [%s]. Expect [%t] but got [%t]", test.s, test.exp, b)
+		}
+	}
+}
+
+func TestPorter2StemString(t *testing.T) {
+	tests := []struct {
+		s, exp string
+	}{
+		{"", ""},
+		{"a", "a"},
+		{"Skies", "sky"},
+		{"dying", "die"},
+		{"news", "news"},
+		{"innings", "inning"},
+		{"proceeding", "proceed"},
+		{"generalization", "general"},
+		{"generously", "generous"},
+		{"communication", "communic"},
+		{"dog's", "dog"},
+		{"dogs'", "dog"},
+		{"'cause", "caus"},
+		{"Youth", "youth"},
+		{"sayyid", "sayyid"},
+		{"crying", "cri"},
+		{"ties", "tie"},
+		{"cries", "cri"},
+		{"gas", "gas"},
+		{"gaps", "gap"},
+		{"hoping", "hope"},
+		{"hopping", "hop"},
+		{"luxuriating", "luxuri"},
+		{"knightly", "knight"},
+	}
+	for _, test := range tests {
+		if stem := Porter2StemString(test.s); stem != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.s, stem, test.exp)
+		}
+	}
+}
+
+func TestPorter2Vocabulary(t *testing.T) {
+	vs := readFields(t, "porter2_voc.txt")
+	os := readFields(t, "porter2_output.txt")
+	if len(vs) != len(os) {
+		t.Fatalf("vocabulary has %d words but output has %d stems", len(vs), len(os))
+	}
+	for i, word := range vs {
+		stem := Porter2StemString(word)
+		if stem != os[i] {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", word, stem, os[i])
+		}
+	}
+}
+
+func BenchmarkPorter2String(b *testing.B) {
+	ss := getVoc()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, s := range ss {
+			stem := Porter2StemString(s)
+			_ = stem
+		}
+	}
+}
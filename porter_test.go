@@ -2,12 +2,8 @@ package porter
 
 import (
 	"bytes"
-	"fmt"
-	"io"
 	"io/ioutil"
-	"net/http"
 	"os"
-	"path"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -281,54 +277,68 @@ func TestMeasure(t *testing.T) {
 	}
 }
 
+// testDir holds the golden files: voc.txt, a vocabulary of English words,
+// and the stems of each word in output.txt (from the C reference
+// implementation) and strict_output.txt (strict rules, from the Snowball
+// Porter stemmer).  See testdata/README.md.  Regenerate the files made by this
+// package with:
+//
+//	go run ./internal/regen -w
 const testDir = "testdata"
 
-const (
-	vocURL    = "http://tartarus.org/martin/PorterStemmer/voc.txt"
-	outputURL = "http://tartarus.org/martin/PorterStemmer/output.txt"
-)
+// ownDepartures holds the words of voc.txt that StemString stems differently
+// from the C reference, by the departure of this package's own described in
+// Options: a step 1a suffix is never the whole word.
+var ownDepartures = map[string]string{
+	"ies": "ie",
+}
 
-// init downloads the appropriate files, if necessary.
-func init() {
-	_, err := os.Stat(testDir)
-	if nil != err {
-		_ = os.Mkdir(testDir, 0755)
+func TestStemString(t *testing.T) {
+
+	v, err := os.Open(filepath.Join(testDir, "voc.txt"))
+	if err != nil {
+		t.Fatalf("%s", err)
 	}
-	_, err = os.Stat(testDir)
-	if nil != err {
-		panic(fmt.Sprintf("The test data folder ([%s]) does not exists (and could not create it).", testDir))
+	defer v.Close()
+	o, err := os.Open(filepath.Join(testDir, "output.txt"))
+	if err != nil {
+		t.Fatalf("%s", err)
 	}
-	for _, u := range []string{vocURL, outputURL} {
-		fname := filepath.Join(testDir, path.Base(u))
-		_, err = os.Stat(fname)
-		if err == nil {
-			continue
-		}
-		resp, err := http.Get(u)
-		if err != nil {
-			panic(fmt.Sprintf("could not download %s", u))
-		}
-		defer resp.Body.Close()
-		fout, err := os.Create(fname)
-		if err != nil {
-			panic(fmt.Sprintf("could not download %s", u))
+	defer o.Close()
+
+	data, err := ioutil.ReadAll(v)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	vs := strings.Fields(string(data))
+
+	data, err = ioutil.ReadAll(o)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	os := strings.Fields(string(data))
+	if len(vs) != len(os) {
+		t.Fatalf("vocabulary has %d words but output has %d stems", len(vs), len(os))
+	}
+	for i, word := range vs {
+		exp := os[i]
+		if own, ok := ownDepartures[word]; ok {
+			exp = own
 		}
-		defer fout.Close()
-		_, err = io.Copy(fout, resp.Body)
-		if err != nil {
-			panic(fmt.Sprintf("could not download %s", u))
+		stem := StemString(word)
+		if stem != exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", word, stem, exp)
 		}
 	}
 }
 
-func TestStemString(t *testing.T) {
-
-	v, err := os.Open(filepath.Join(testDir, path.Base(vocURL)))
+func TestPorterStrict(t *testing.T) {
+	v, err := os.Open(filepath.Join(testDir, "voc.txt"))
 	if err != nil {
 		t.Fatalf("%s", err)
 	}
 	defer v.Close()
-	o, err := os.Open(filepath.Join(testDir, path.Base(outputURL)))
+	o, err := os.Open(filepath.Join(testDir, "strict_output.txt"))
 	if err != nil {
 		t.Fatalf("%s", err)
 	}
@@ -345,14 +355,47 @@ func TestStemString(t *testing.T) {
 		t.Fatalf("%s", err)
 	}
 	os := strings.Fields(string(data))
+	if len(vs) != len(os) {
+		t.Fatalf("vocabulary has %d words but output has %d stems", len(vs), len(os))
+	}
+	p := New(Options{Strict: true})
 	for i, word := range vs {
-		stem := StemString(word)
+		stem := p.StemString(word)
 		if stem != os[i] {
 			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", word, stem, os[i])
 		}
 	}
 }
 
+func TestPorterDepartures(t *testing.T) {
+	tests := []struct {
+		s, def, strict string
+	}{
+		{"analogy", "analog", "analogi"},
+		{"possibly", "possibl", "possibli"},
+		{"conformably", "conform", "conform"},
+		// This package's own departure, not the C reference's: step 1a never
+		// matches the whole word, where the C reference gives "i".
+		{"ies", "ie", "i"},
+		{"is", "is", "i"},
+		{"as", "as", "a"},
+		{"", "", ""},
+	}
+	def := New(Options{})
+	strict := New(Options{Strict: true})
+	for _, test := range tests {
+		if stem := StemString(test.s); stem != test.def {
+			t.Errorf("StemString(%q) = %q, expected %q", test.s, stem, test.def)
+		}
+		if stem := def.StemString(test.s); stem != test.def {
+			t.Errorf("default StemString(%q) = %q, expected %q", test.s, stem, test.def)
+		}
+		if stem := strict.StemString(test.s); stem != test.strict {
+			t.Errorf("strict StemString(%q) = %q, expected %q", test.s, stem, test.strict)
+		}
+	}
+}
+
 func TestStemWithoutLowerCasing(t *testing.T) {
 	tests := []struct {
 		s   []rune
@@ -423,9 +466,9 @@ func TestStep1a(t *testing.T) {
 		for i := 0; i < len(test.s); i++ {
 			stem := make([]rune, len(test.s))
 			copy(stem, test.s)
-			stem = step1a(stem)
+			stem = Step1a(stem)
 			if !stemEqual(stem, test.exp) {
-				t.Errorf("Did NOT get what was expected for calling step1a() on [%s]. Expect [%s] but got [%s]", string(test.s), string(test.exp), string(stem))
+				t.Errorf("Did NOT get what was expected for calling Step1a() on [%s]. Expect [%s] but got [%s]", string(test.s), string(test.exp), string(stem))
 			}
 		}
 	}
@@ -457,9 +500,9 @@ func TestStep1b(t *testing.T) {
 	for _, test := range tests {
 		stem := make([]rune, len(test.s))
 		copy(stem, test.s)
-		stem = step1b(stem)
+		stem = Step1b(stem)
 		if !stemEqual(stem, test.exp) {
-			t.Errorf("Did NOT get what was expected for calling step1b() on [%s]. Expect [%s] but got [%s]", string(test.s), string(test.exp), string(stem))
+			t.Errorf("Did NOT get what was expected for calling Step1b() on [%s]. Expect [%s] but got [%s]", string(test.s), string(test.exp), string(stem))
 		}
 	}
 }
@@ -476,9 +519,9 @@ func TestStep1c(t *testing.T) {
 	for _, test := range tests {
 		stem := make([]rune, len(test.s))
 		copy(stem, test.s)
-		stem = step1c(stem)
+		stem = Step1c(stem)
 		if !stemEqual(stem, test.exp) {
-			t.Errorf("Did NOT get what was expected for calling step1c() on [%s]. Expect [%s] but got [%s]", string(test.s), string(test.exp), string(stem))
+			t.Errorf("Did NOT get what was expected for calling Step1c() on [%s]. Expect [%s] but got [%s]", string(test.s), string(test.exp), string(stem))
 		}
 	}
 }
@@ -513,9 +556,9 @@ func TestStep2(t *testing.T) {
 	for _, test := range tests {
 		stem := make([]rune, len(test.s))
 		copy(stem, test.s)
-		stem = step2(stem)
+		stem = Step2(stem)
 		if !stemEqual(stem, test.exp) {
-			t.Errorf("Did NOT get what was expected for calling step2() on [%s]. Expect [%s] but got [%s]", string(test.s), string(test.exp), string(stem))
+			t.Errorf("Did NOT get what was expected for calling Step2() on [%s]. Expect [%s] but got [%s]", string(test.s), string(test.exp), string(stem))
 		}
 	}
 }
@@ -536,9 +579,9 @@ func TestStep3(t *testing.T) {
 	for _, test := range tests {
 		stem := make([]rune, len(test.s))
 		copy(stem, test.s)
-		stem = step3(stem)
+		stem = Step3(stem)
 		if !stemEqual(stem, test.exp) {
-			t.Errorf("Did NOT get what was expected for calling step3() on [%s]. Expect [%s] but got [%s]", string(test.s), string(test.exp), string(stem))
+			t.Errorf("Did NOT get what was expected for calling Step3() on [%s]. Expect [%s] but got [%s]", string(test.s), string(test.exp), string(stem))
 		}
 	}
 }
@@ -571,9 +614,9 @@ func TestStep4(t *testing.T) {
 	for _, test := range tests {
 		stem := make([]rune, len(test.s))
 		copy(stem, test.s)
-		stem = step4(stem)
+		stem = Step4(stem)
 		if !stemEqual(stem, test.exp) {
-			t.Errorf("Did NOT get what was expected for calling step4() on [%s]. Expect [%s] but got [%s]", string(test.s), string(test.exp), string(stem))
+			t.Errorf("Did NOT get what was expected for calling Step4() on [%s]. Expect [%s] but got [%s]", string(test.s), string(test.exp), string(stem))
 		}
 	}
 }
@@ -590,9 +633,9 @@ func TestStep5a(t *testing.T) {
 	for _, test := range tests {
 		stem := make([]rune, len(test.s))
 		copy(stem, test.s)
-		stem = step5a(stem)
+		stem = Step5a(stem)
 		if !stemEqual(stem, test.exp) {
-			t.Errorf("Did NOT get what was expected for calling step5a() on [%s]. Expect [%s] but got [%s]", string(test.s), string(test.exp), string(stem))
+			t.Errorf("Did NOT get what was expected for calling Step5a() on [%s]. Expect [%s] but got [%s]", string(test.s), string(test.exp), string(stem))
 		}
 	}
 }
@@ -608,9 +651,9 @@ func TestStep5b(t *testing.T) {
 	for _, test := range tests {
 		stem := make([]rune, len(test.s))
 		copy(stem, test.s)
-		stem = step5b(stem)
+		stem = Step5b(stem)
 		if !stemEqual(stem, test.exp) {
-			t.Errorf("Did NOT get what was expected for calling step5b() on [%s]. Expect [%s] but got [%s]", string(test.s), string(test.exp), string(stem))
+			t.Errorf("Did NOT get what was expected for calling Step5b() on [%s]. Expect [%s] but got [%s]", string(test.s), string(test.exp), string(stem))
 		}
 	}
 }
@@ -628,6 +671,16 @@ func getVoc() []string {
 	return strings.Fields(string(data))
 }
 
+// readFields returns the whitespace separated fields of a file in the test
+// data folder.
+func readFields(t testing.TB, name string) []string {
+	data, err := ioutil.ReadFile(filepath.Join(testDir, name))
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	return strings.Fields(string(data))
+}
+
 func BenchmarkString(b *testing.B) {
 	ss := getVoc()
 	b.ResetTimer()
@@ -660,10 +713,11 @@ func BenchmarkStep1A(b *testing.B) {
 	for i, s := range ss {
 		rs[i] = []rune(s)
 	}
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		for _, s := range rs {
-			stem := step1a(s)
+			stem := Step1a(s)
 			_ = stem
 		}
 	}
@@ -675,10 +729,11 @@ func BenchmarkStep1B(b *testing.B) {
 	for i, s := range ss {
 		rs[i] = []rune(s)
 	}
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		for _, s := range rs {
-			stem := step1b(s)
+			stem := Step1b(s)
 			_ = stem
 		}
 	}
@@ -690,10 +745,11 @@ func BenchmarkStep1C(b *testing.B) {
 	for i, s := range ss {
 		rs[i] = []rune(s)
 	}
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		for _, s := range rs {
-			stem := step1c(s)
+			stem := Step1c(s)
 			_ = stem
 		}
 	}
@@ -705,10 +761,11 @@ func BenchmarkStep2(b *testing.B) {
 	for i, s := range ss {
 		rs[i] = []rune(s)
 	}
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		for _, s := range rs {
-			stem := step2(s)
+			stem := Step2(s)
 			_ = stem
 		}
 	}
@@ -720,10 +777,11 @@ func BenchmarkStep3(b *testing.B) {
 	for i, s := range ss {
 		rs[i] = []rune(s)
 	}
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		for _, s := range rs {
-			stem := step3(s)
+			stem := Step3(s)
 			_ = stem
 		}
 	}
@@ -735,10 +793,11 @@ func BenchmarkStep4(b *testing.B) {
 	for i, s := range ss {
 		rs[i] = []rune(s)
 	}
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		for _, s := range rs {
-			stem := step1a(s)
+			stem := Step4(s)
 			_ = stem
 		}
 	}
@@ -750,10 +809,11 @@ func BenchmarkStep5A(b *testing.B) {
 	for i, s := range ss {
 		rs[i] = []rune(s)
 	}
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		for _, s := range rs {
-			stem := step5a(s)
+			stem := Step5a(s)
 			_ = stem
 		}
 	}
@@ -765,10 +825,11 @@ func BenchmarkStep5B(b *testing.B) {
 	for i, s := range ss {
 		rs[i] = []rune(s)
 	}
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		for _, s := range rs {
-			stem := step5b(s)
+			stem := Step5b(s)
 			_ = stem
 		}
 	}
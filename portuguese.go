@@ -0,0 +1,254 @@
+package porter
+
+import (
+	"unicode"
+)
+
+// This file implements the Portuguese stemmer from the Snowball project.  For
+// the algorithm, see:
+//
+// http://snowball.tartarus.org/algorithms/portuguese/stemmer.html
+//
+// The nasal vowels ã and õ are written as a~ and o~ while the word is
+// stemmed, and changed back once it is.  So a word with an ã or õ is stemmed
+// into new runes, but the stem is never longer than the word.
+
+// The suffixes of each step.  Only the longest suffix that a word ends with
+// is ever considered.
+var (
+	portugueseStandardSuffixes = []string{
+		"eza", "ezas", "ico", "ica", "icos", "icas", "ismo", "ismos", "ável",
+		"ível", "ista", "istas", "oso", "osa", "osos", "osas", "amento",
+		"amentos", "imento", "imentos", "adora", "ador", "aça~o", "adoras",
+		"adores", "aço~es", "ante", "antes", "ância",
+		"logia", "logias",
+		"uça~o", "uço~es",
+		"ência", "ências",
+		"amente",
+		"mente",
+		"idade", "idades",
+		"iva", "ivo", "ivas", "ivos",
+		"ira", "iras",
+	}
+	portugueseVerbSuffixes = []string{
+		"ada", "ida", "ia", "aria", "eria", "iria", "ará", "ara", "erá", "era",
+		"irá", "ava", "asse", "esse", "isse", "aste", "este", "iste", "ei",
+		"arei", "erei", "irei", "am", "iam", "ariam", "eriam", "iriam",
+		"aram", "eram", "iram", "avam", "em", "arem", "erem", "irem", "assem",
+		"essem", "issem", "ado", "ido", "ando", "endo", "indo", "ara~o",
+		"era~o", "ira~o", "ar", "er", "ir", "as", "adas", "idas", "ias",
+		"arias", "erias", "irias", "arás", "aras", "erás", "eras", "irás",
+		"avas", "es", "ardes", "erdes", "irdes", "ares", "eres", "ires",
+		"asses", "esses", "isses", "astes", "estes", "istes", "is", "ais",
+		"eis", "íeis", "aríeis", "eríeis", "iríeis", "áreis", "areis",
+		"éreis", "ereis", "íreis", "ireis", "ásseis", "ésseis", "ísseis",
+		"áveis", "ados", "idos", "ámos", "amos", "íamos", "aríamos",
+		"eríamos", "iríamos", "áramos", "éramos", "íramos", "ávamos", "emos",
+		"aremos", "eremos", "iremos", "ássemos", "êssemos", "íssemos",
+		"imos", "armos", "ermos", "irmos", "eu", "iu", "ou", "ira", "iras",
+	}
+	portugueseResidualSuffixes = []string{"os", "a", "i", "o", "á", "í", "ó"}
+)
+
+// isPortugueseVowel returns true if the rune is a vowel.  The a and o of a
+// nasal vowel are vowels, but its ~ is not.
+func isPortugueseVowel(r rune) bool {
+	switch r {
+	case 'a', 'e', 'i', 'o', 'u', 'á', 'é', 'í', 'ó', 'ú', 'â', 'ê', 'ô':
+		return true
+	}
+	return false
+}
+
+// portuguesePrelude replaces each ã and õ with a~ and o~.  If there are any,
+// the word is copied into new runes.
+func portuguesePrelude(s []rune) []rune {
+	n := 0
+	for _, r := range s {
+		if r == 'ã' || r == 'õ' {
+			n++
+		}
+	}
+	if n == 0 {
+		return s
+	}
+	t := make([]rune, 0, len(s)+n)
+	for _, r := range s {
+		switch r {
+		case 'ã':
+			t = append(t, 'a', '~')
+		case 'õ':
+			t = append(t, 'o', '~')
+		default:
+			t = append(t, r)
+		}
+	}
+	return t
+}
+
+// portuguesePostlude replaces each a~ and o~ with ã and õ.  The word is
+// shortened in place.
+func portuguesePostlude(s []rune) []rune {
+	j := 0
+	for i := 0; i < len(s); i, j = i+1, j+1 {
+		s[j] = s[i]
+		if i+1 == len(s) || s[i+1] != '~' {
+			continue
+		}
+		switch s[i] {
+		case 'a':
+			s[j] = 'ã'
+			i++
+		case 'o':
+			s[j] = 'õ'
+			i++
+		}
+	}
+	return s[:j]
+}
+
+// portugueseStandardSuffix removes or replaces the longest standard suffix.
+// It returns false if no suffix was removed.
+func portugueseStandardSuffix(s []rune, rv, r1, r2 int) ([]rune, bool) {
+	suffix, i := longestSuffix(s, 0, portugueseStandardSuffixes)
+	switch suffix {
+	case "":
+		return s, false
+	case "amente":
+		if i < r1 {
+			return s, false
+		}
+		s = s[:i]
+		suffix, i = longestSuffix(s, 0, []string{"iv", "os", "ic", "ad"})
+		if suffix != "" && i >= r2 {
+			s = s[:i]
+			if suffix == "iv" && endsWithString(s, "at") && i-2 >= r2 {
+				s = s[:i-2]
+			}
+		}
+		return s, true
+	case "ira", "iras":
+		if i < rv || i == 0 || s[i-1] != 'e' {
+			return s, false
+		}
+		return replaceSuffix(s, len(s)-i, "ir"), true
+	}
+	if i < r2 {
+		return s, false
+	}
+	switch suffix {
+	case "logia", "logias":
+		s = replaceSuffix(s, len(s)-i, "log")
+	case "uça~o", "uço~es":
+		s = replaceSuffix(s, len(s)-i, "u")
+	case "ência", "ências":
+		s = replaceSuffix(s, len(s)-i, "ente")
+	case "mente":
+		s = s[:i]
+		if suffix, i = longestSuffix(s, 0, []string{"ante", "avel", "ível"}); suffix != "" && i >= r2 {
+			s = s[:i]
+		}
+	case "idade", "idades":
+		s = s[:i]
+		if suffix, i = longestSuffix(s, 0, []string{"abil", "ic", "iv"}); suffix != "" && i >= r2 {
+			s = s[:i]
+		}
+	case "iva", "ivo", "ivas", "ivos":
+		s = s[:i]
+		if endsWithString(s, "at") && i-2 >= r2 {
+			s = s[:i-2]
+		}
+	default:
+		s = s[:i]
+	}
+	return s, true
+}
+
+// portugueseVerbSuffix removes the longest verb suffix in RV.
+func portugueseVerbSuffix(s []rune, rv int) ([]rune, bool) {
+	suffix, i := longestSuffix(s, rv, portugueseVerbSuffixes)
+	if suffix == "" {
+		return s, false
+	}
+	return s[:i], true
+}
+
+// portugueseResidualSuffix removes a final os, a, i, o, á, í or ó in RV.  It
+// is only used when no other suffix was removed.
+func portugueseResidualSuffix(s []rune, rv int) []rune {
+	if suffix, i := longestSuffix(s, 0, portugueseResidualSuffixes); suffix != "" && i >= rv {
+		s = s[:i]
+	}
+	return s
+}
+
+// portugueseResidualForm removes a final e, é or ê in RV, and then the u of a
+// gu or the i of a ci if it is in RV.  A final ç becomes c.
+func portugueseResidualForm(s []rune, rv int) []rune {
+	n := len(s)
+	if n == 0 {
+		return s
+	}
+	switch s[n-1] {
+	case 'e', 'é', 'ê':
+		if n-1 < rv {
+			return s
+		}
+		s, n = s[:n-1], n-1
+		if n >= 2 && n-1 >= rv && (s[n-1] == 'u' && s[n-2] == 'g' || s[n-1] == 'i' && s[n-2] == 'c') {
+			s = s[:n-1]
+		}
+	case 'ç':
+		s[n-1] = 'c'
+	}
+	return s
+}
+
+// PortugueseStemString converts a string to a rune array, then stems the
+// result with the Portuguese algorithm.
+func PortugueseStemString(s string) string {
+	ra := []rune(s)
+	ra = PortugueseStem(ra)
+	return string(ra)
+}
+
+// PortugueseStem converts the runes to lower case, then stems the lowercase
+// runes with the Portuguese algorithm.
+func PortugueseStem(s []rune) []rune {
+	if len(s) == 0 {
+		return s
+	}
+	for i := 0; i < len(s); i++ {
+		s[i] = unicode.ToLower(s[i])
+	}
+	return PortugueseStemWithoutLowerCasing(s)
+}
+
+// PortugueseStemWithoutLowerCasing applies the Portuguese stemming assuming
+// that the runes are lowercase.
+func PortugueseStemWithoutLowerCasing(s []rune) []rune {
+	s = portuguesePrelude(s)
+	rv, r1, r2 := romanceRegions(s, isPortugueseVowel)
+
+	s, ok := portugueseStandardSuffix(s, rv, r1, r2)
+	if !ok {
+		s, ok = portugueseVerbSuffix(s, rv)
+	}
+	if ok {
+		if n := len(s); n >= 2 && s[n-1] == 'i' && s[n-2] == 'c' && n-1 >= rv {
+			s = s[:n-1]
+		}
+	} else {
+		s = portugueseResidualSuffix(s, rv)
+	}
+	s = portugueseResidualForm(s, rv)
+
+	return portuguesePostlude(s)
+}
+
+// portuguese is the Portuguese algorithm as a Stemmer.
+type portuguese struct{}
+
+func (portuguese) StemString(s string) string             { return PortugueseStemString(s) }
+func (portuguese) Stem(s []rune) []rune                   { return PortugueseStem(s) }
+func (portuguese) StemWithoutLowerCasing(s []rune) []rune { return PortugueseStemWithoutLowerCasing(s) }
@@ -0,0 +1,92 @@
+package porter
+
+import (
+	"testing"
+)
+
+func TestPortuguesePrelude(t *testing.T) {
+	tests := []struct {
+		s, exp string
+	}{
+		{"informações", "informaço~es"},
+		{"mãe", "ma~e"},
+		{"casa", "casa"},
+	}
+	for _, test := range tests {
+		s := []rune(test.s)
+		if p := string(portuguesePrelude(s)); p != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.s, p, test.exp)
+		}
+		if p := string(portuguesePostlude([]rune(test.exp))); p != test.s {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.exp, p, test.s)
+		}
+	}
+}
+
+func TestPortugueseStemString(t *testing.T) {
+	tests := []struct {
+		s, exp string
+	}{
+		{"", ""},
+		{"a", "a"},
+		{"Informação", "inform"},
+		{"informações", "inform"},
+		{"considerações", "consider"},
+		{"possível", "possível"},
+		{"cantaríamos", "cant"},
+		{"cantássemos", "cant"},
+		{"nacionalidade", "nacional"},
+		{"rapidamente", "rapid"},
+		{"felizmente", "feliz"},
+		{"lógica", "lógic"},
+		{"utilização", "utiliz"},
+		{"frequência", "frequênc"},
+		{"brasileiras", "brasileir"},
+		{"chegue", "cheg"},
+		{"conhece", "conhec"},
+		{"portuguesa", "portugues"},
+		{"alemães", "alemã"},
+		{"lições", "liçõ"},
+		{"maçã", "maçã"},
+	}
+	for _, test := range tests {
+		if stem := PortugueseStemString(test.s); stem != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.s, stem, test.exp)
+		}
+	}
+}
+
+func TestPortugueseNasalVowels(t *testing.T) {
+	// The nasal vowels are written as two runes while the word is stemmed, so
+	// it cannot be stemmed in place.
+	s := []rune("lições")
+	stem := PortugueseStemWithoutLowerCasing(s)
+	if string(stem) != "liçõ" || string(s) != "lições" {
+		t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", string(s), string(stem), "liçõ")
+	}
+}
+
+func TestPortugueseVocabulary(t *testing.T) {
+	vs := readFields(t, "portuguese_voc.txt")
+	os := readFields(t, "portuguese_output.txt")
+	if len(vs) != len(os) {
+		t.Fatalf("vocabulary has %d words but output has %d stems", len(vs), len(os))
+	}
+	for i, word := range vs {
+		stem := PortugueseStemString(word)
+		if stem != os[i] {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", word, stem, os[i])
+		}
+	}
+}
+
+func BenchmarkPortugueseString(b *testing.B) {
+	ss := readFields(b, "portuguese_voc.txt")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, s := range ss {
+			stem := PortugueseStemString(s)
+			_ = stem
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package porter
+
+import (
+	"unicode/utf8"
+)
+
+// This file holds what the Snowball stemmers for languages other than English
+// have in common: finding the regions of a word, and matching suffixes which
+// are not ASCII.
+
+// regionStart returns the index after the first non-vowel following a vowel,
+// at or after index i.  It returns len(s) if there is none.  R1 starts at
+// regionStart(s, 0, isVowel), and R2 at regionStart(s, r1, isVowel).
+func regionStart(s []rune, i int, isVowel func(rune) bool) int {
+	for ; i < len(s) && !isVowel(s[i]); i++ {
+	}
+	for ; i < len(s) && isVowel(s[i]); i++ {
+	}
+	if i >= len(s) {
+		return len(s)
+	}
+	return i + 1
+}
+
+// endsWithString checks if a word ends with a suffix, which may be the whole
+// word.  Unlike endsWith, the suffix may have runes which are not ASCII.
+func endsWithString(s []rune, suffix string) bool {
+	i := len(s)
+	for j := len(suffix); j > 0; {
+		r, size := utf8.DecodeLastRuneInString(suffix[:j])
+		j -= size
+		i--
+		if i < 0 || s[i] != r {
+			return false
+		}
+	}
+	return true
+}
+
+// longestSuffix returns the longest of the suffixes that the word ends with,
+// and the index where it starts, considering only the suffixes which start
+// at or after index lb.  It returns "" and len(s) if there is none.
+func longestSuffix(s []rune, lb int, suffixes []string) (string, int) {
+	longest, start := "", len(s)
+	for _, suffix := range suffixes {
+		i := len(s) - utf8.RuneCountInString(suffix)
+		if i < lb || i >= start || !endsWithString(s, suffix) {
+			continue
+		}
+		longest, start = suffix, i
+	}
+	return longest, start
+}
+
+// romanceRegions returns the start of the RV, R1 and R2 regions, as the
+// Spanish and Portuguese stemmers define them.  If the second letter is not a
+// vowel, RV starts after the next vowel.  If the first two letters are
+// vowels, it starts after the next non-vowel.  Otherwise it starts after the
+// third letter.
+func romanceRegions(s []rune, isVowel func(rune) bool) (rv, r1, r2 int) {
+	r1 = regionStart(s, 0, isVowel)
+	return romanceRVStart(s, isVowel), r1, regionStart(s, r1, isVowel)
+}
+
+// romanceRVStart returns the start of RV for romanceRegions.  It returns
+// len(s) if the word has no such region.
+func romanceRVStart(s []rune, isVowel func(rune) bool) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+	if !isVowel(s[1]) {
+		for i := 2; i < len(s); i++ {
+			if isVowel(s[i]) {
+				return i + 1
+			}
+		}
+		return len(s)
+	}
+	if isVowel(s[0]) {
+		for i := 2; i < len(s); i++ {
+			if !isVowel(s[i]) {
+				return i + 1
+			}
+		}
+		return len(s)
+	}
+	if len(s) < 3 {
+		return len(s)
+	}
+	return 3
+}
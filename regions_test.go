@@ -0,0 +1,87 @@
+package porter
+
+import (
+	"testing"
+)
+
+func TestRegionStart(t *testing.T) {
+	tests := []struct {
+		s      string
+		r1, r2 string
+	}{
+		{"beautiful", "iful", "ul"},
+		{"beauty", "y", ""},
+		{"beau", "", ""},
+		{"animadversion", "imadversion", "adversion"},
+		{"", "", ""},
+	}
+	for _, test := range tests {
+		s := []rune(test.s)
+		r1 := regionStart(s, 0, isPorter2Vowel)
+		r2 := regionStart(s, r1, isPorter2Vowel)
+		if string(s[r1:]) != test.r1 || string(s[r2:]) != test.r2 {
+			t.Errorf("Did NOT get what was expected for calling regionStart() on [%s]. Expect R1 [%s] and R2 [%s] but got [%s] and [%s]", test.s, test.r1, test.r2, string(s[r1:]), string(s[r2:]))
+		}
+	}
+}
+
+func TestEndsWithString(t *testing.T) {
+	tests := []struct {
+		s, suffix string
+		exp       bool
+	}{
+		{"nationalité", "ité", true},
+		{"nationalité", "ite", false},
+		{"été", "été", true},
+		{"té", "été", false},
+		{"", "", true},
+	}
+	for _, test := range tests {
+		if b := endsWithString([]rune(test.s), test.suffix); b != test.exp {
+			t.Errorf("Did NOT get what was expected for calling endsWithString() on [%s] and [%s]. Expect [%t] but got [%t]", test.s, test.suffix, test.exp, b)
+		}
+	}
+}
+
+func TestLongestSuffix(t *testing.T) {
+	suffixes := []string{"e", "ée", "ées", "s"}
+	tests := []struct {
+		s        string
+		lb       int
+		suffix   string
+		expStart int
+	}{
+		{"aimées", 0, "ées", 3},
+		{"aimées", 4, "s", 5},
+		{"aimée", 0, "ée", 3},
+		{"aimer", 0, "", 5},
+		{"s", 1, "", 1},
+	}
+	for _, test := range tests {
+		suffix, i := longestSuffix([]rune(test.s), test.lb, suffixes)
+		if suffix != test.suffix || i != test.expStart {
+			t.Errorf("Did NOT get what was expected for calling longestSuffix() on [%s] from %d. Expect [%s] at %d but got [%s] at %d", test.s, test.lb, test.suffix, test.expStart, suffix, i)
+		}
+	}
+}
+
+func TestRomanceRegions(t *testing.T) {
+	tests := []struct {
+		s          string
+		rv, r1, r2 string
+	}{
+		{"macho", "ho", "ho", ""},
+		{"oliva", "va", "iva", "a"},
+		{"trabajo", "bajo", "ajo", "o"},
+		{"áureo", "eo", "eo", ""},
+		{"yo", "", "", ""},
+		{"a", "", "", ""},
+	}
+	for _, test := range tests {
+		s := []rune(test.s)
+		rv, r1, r2 := romanceRegions(s, isSpanishVowel)
+		if string(s[rv:]) != test.rv || string(s[r1:]) != test.r1 || string(s[r2:]) != test.r2 {
+			t.Errorf("Did NOT get what was expected for calling romanceRegions() on [%s]. Expect RV [%s], R1 [%s] and R2 [%s] but got [%s], [%s] and [%s]", test.s, test.rv, test.r1, test.r2, string(s[rv:]), string(s[r1:]), string(s[r2:]))
+		}
+	}
+}
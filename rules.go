@@ -0,0 +1,387 @@
+package porter
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Condition is a test on the stem that is left when a rule's suffix is
+// removed, like the conditions in the Porter paper (m>0, *v*, *d, ...).  The
+// zero Condition always holds.
+type Condition struct {
+	op      conditionOp
+	name    string
+	n       int
+	letters string
+	conds   []Condition
+	holds   func(stem []rune) bool
+}
+
+// conditionOp is the kind of test a Condition makes.  Every kind but
+// opFunc is evaluated without calling a function value, so that the stems
+// given to the built in conditions never escape to the heap.
+type conditionOp int
+
+const (
+	opAlways conditionOp = iota
+	opFunc
+	opMeasureGreater
+	opMeasureEquals
+	opContainsVowel
+	opDoubleConsonant
+	opCVC
+	opEndsWithAny
+	opAnd
+	opOr
+	opNot
+)
+
+// NewCondition returns a Condition with a name, which is how it is written
+// in rules and traces, and a test.
+func NewCondition(name string, holds func(stem []rune) bool) Condition {
+	return Condition{op: opFunc, name: name, holds: holds}
+}
+
+// Holds returns true if the stem satisfies the condition.
+func (c Condition) Holds(stem []rune) bool {
+	if c.op == opFunc {
+		return c.holds(stem)
+	}
+	w := newWord(stem)
+	return c.eval(&w, len(stem))
+}
+
+// eval returns true if the first k letters of a word satisfy a built in
+// condition.  It is false for a condition made by NewCondition.
+func (c *Condition) eval(w *word, k int) bool {
+	switch c.op {
+	case opAlways:
+		return true
+	case opMeasureGreater:
+		return int(w.measure(k)) > c.n
+	case opMeasureEquals:
+		return int(w.measure(k)) == c.n
+	case opContainsVowel:
+		return w.containsVowel(k)
+	case opDoubleConsonant:
+		return w.endsDoubleConsonant(k)
+	case opCVC:
+		return w.endsCVCNotWXY(k)
+	case opEndsWithAny:
+		return k > 0 && strings.ContainsRune(c.letters, w.s[k-1])
+	case opAnd:
+		for i := range c.conds {
+			if !c.conds[i].eval(w, k) {
+				return false
+			}
+		}
+		return true
+	case opOr:
+		for i := range c.conds {
+			if c.conds[i].eval(w, k) {
+				return true
+			}
+		}
+		return false
+	case opNot:
+		return !c.conds[0].eval(w, k)
+	}
+	return false
+}
+
+// String returns the name of the condition, or "" for the zero Condition.
+func (c Condition) String() string {
+	return c.name
+}
+
+// The conditions of the Porter paper.  The measure m of a stem is the number
+// of times a run of vowels is followed by a run of consonants.
+var (
+	// ContainsVowel is *v*: the stem contains a vowel.
+	ContainsVowel = Condition{op: opContainsVowel, name: "*v*"}
+
+	// EndsDoubleConsonant is *d: the stem ends with a double consonant.
+	EndsDoubleConsonant = Condition{op: opDoubleConsonant, name: "*d"}
+
+	// EndsCVC is *o: the stem ends consonant, vowel, consonant, where the
+	// last consonant is not w, x or y.
+	EndsCVC = Condition{op: opCVC, name: "*o"}
+)
+
+// MeasureGreaterThan returns the condition m>n.
+func MeasureGreaterThan(n int) Condition {
+	return Condition{op: opMeasureGreater, name: "m>" + strconv.Itoa(n), n: n}
+}
+
+// MeasureEquals returns the condition m=n.
+func MeasureEquals(n int) Condition {
+	return Condition{op: opMeasureEquals, name: "m=" + strconv.Itoa(n), n: n}
+}
+
+// EndsWithAny returns the condition that the stem ends with one of the
+// letters, e.g. EndsWithAny("st") is (*S or *T).
+func EndsWithAny(letters string) Condition {
+	var names []string
+	for _, r := range letters {
+		names = append(names, "*"+string(unicode.ToUpper(r)))
+	}
+	return Condition{op: opEndsWithAny, name: strings.Join(names, " or "), letters: letters}
+}
+
+// combine returns a condition made of others.  If any of them was made by
+// NewCondition, so is the result, using holds.
+func combine(op conditionOp, name string, conds []Condition, holds func(stem []rune) bool) Condition {
+	for _, c := range conds {
+		if c.op == opFunc {
+			return NewCondition(name, holds)
+		}
+	}
+	return Condition{op: op, name: name, conds: conds}
+}
+
+// And returns the condition that all of the conditions hold.
+func And(conds ...Condition) Condition {
+	conds = append([]Condition(nil), conds...)
+	return combine(opAnd, joinConditions(conds, " and ", " or "), conds, func(stem []rune) bool {
+		for _, c := range conds {
+			if !c.Holds(stem) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// Or returns the condition that at least one of the conditions holds.
+func Or(conds ...Condition) Condition {
+	conds = append([]Condition(nil), conds...)
+	return combine(opOr, joinConditions(conds, " or ", " and "), conds, func(stem []rune) bool {
+		for _, c := range conds {
+			if c.Holds(stem) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Not returns the condition that c does not hold.
+func Not(c Condition) Condition {
+	name := c.name
+	if strings.Contains(name, " ") {
+		name = "(" + name + ")"
+	}
+	return combine(opNot, "not "+name, []Condition{c}, func(stem []rune) bool {
+		return !c.Holds(stem)
+	})
+}
+
+// joinConditions writes conditions joined by op, with parentheses around any
+// that contain other.
+func joinConditions(conds []Condition, op, other string) string {
+	names := make([]string, 0, len(conds))
+	for _, c := range conds {
+		if c.name == "" {
+			continue
+		}
+		if strings.Contains(c.name, other) {
+			names = append(names, "("+c.name+")")
+		} else {
+			names = append(names, c.name)
+		}
+	}
+	return strings.Join(names, op)
+}
+
+// Rule replaces a suffix of a word, if what is left of the word satisfies a
+// condition.  A suffix only matches if at least one rune is left before it.
+type Rule struct {
+	Suffix, Replacement string
+	Condition           Condition
+}
+
+// String writes a rule the way the paper does, e.g. "(m>0) ATIONAL -> ATE".
+func (r Rule) String() string {
+	return ruleText(r.Condition.String(), r.Suffix, r.Replacement)
+}
+
+// matches returns true if the suffix of the rule matches s, leaving at least
+// one rune before it.
+func (r *Rule) matches(s []rune) bool {
+	i := len(s)
+	for j := len(r.Suffix); j > 0; {
+		c, size := utf8.DecodeLastRuneInString(r.Suffix[:j])
+		j -= size
+		i--
+		if i < 1 || s[i] != c {
+			return false
+		}
+	}
+	return true
+}
+
+// stem returns s without the suffix of the rule.
+func (r *Rule) stem(s []rune) []rune {
+	return s[:len(s)-utf8.RuneCountInString(r.Suffix)]
+}
+
+// replace replaces the suffix of the rule in s with its replacement.  If the
+// replacement is longer than the suffix, the word grows into new runes, so
+// the runes after s are never written.
+func (r *Rule) replace(s []rune) []rune {
+	s = r.stem(s[:len(s):len(s)])
+	for _, c := range r.Replacement {
+		s = append(s, c)
+	}
+	return s
+}
+
+// Step is a list of rules, which are tried in order.  Only the first rule
+// whose suffix matches is considered: if its condition does not hold, the
+// word is left as it is.
+type Step []Rule
+
+// match returns the first rule whose suffix matches s, or nil.
+func (st Step) match(s []rune) *Rule {
+	for i := range st {
+		if st[i].matches(s) {
+			return &st[i]
+		}
+	}
+	return nil
+}
+
+// Apply applies the step to the word, in place.
+func (st Step) Apply(s []rune) []rune {
+	if r := st.match(s); r != nil && r.Condition.Holds(r.stem(s)) {
+		return r.replace(s)
+	}
+	return s
+}
+
+// RuleStemmer is a suffix-stripping Stemmer made of steps of rules, which
+// are applied in order.  It is safe for concurrent use, as long as the steps
+// are not changed.
+//
+// For example, a stemmer that only removes plurals:
+//
+//	s := &RuleStemmer{Steps: []Step{{
+//		{Suffix: "sses", Replacement: "ss"},
+//		{Suffix: "ies", Replacement: "i"},
+//		{Suffix: "ss", Replacement: "ss"},
+//		{Suffix: "s", Replacement: ""},
+//	}}}
+type RuleStemmer struct {
+	Steps []Step
+}
+
+// StemString converts a string to a rune array, then stems the result.
+func (rs *RuleStemmer) StemString(s string) string {
+	return string(rs.Stem([]rune(s)))
+}
+
+// Stem converts the runes to lower case, then stems the lowercase runes.
+func (rs *RuleStemmer) Stem(s []rune) []rune {
+	for i := 0; i < len(s); i++ {
+		s[i] = unicode.ToLower(s[i])
+	}
+	return rs.StemWithoutLowerCasing(s)
+}
+
+// StemWithoutLowerCasing applies the steps assuming that the runes are
+// lowercase.
+func (rs *RuleStemmer) StemWithoutLowerCasing(s []rune) []rune {
+	for _, st := range rs.Steps {
+		s = st.Apply(s)
+	}
+	return s
+}
+
+// The rules of steps 2 to 4 of the Porter algorithm.
+var (
+	m0 = MeasureGreaterThan(0)
+	m1 = MeasureGreaterThan(1)
+
+	porterStep2 = Step{
+		{"ational", "ate", m0},
+		{"tional", "tion", m0},
+		{"enci", "ence", m0},
+		{"anci", "ance", m0},
+		{"izer", "ize", m0},
+		{"bli", "ble", m0}, // --DEPARTURE--
+		{"alli", "al", m0},
+		{"entli", "ent", m0},
+		{"eli", "e", m0},
+		{"ousli", "ous", m0},
+		{"ization", "ize", m0},
+		{"ation", "ate", m0},
+		{"ator", "ate", m0},
+		{"alism", "al", m0},
+		{"iveness", "ive", m0},
+		{"fulness", "ful", m0},
+		{"ousness", "ous", m0},
+		{"aliti", "al", m0},
+		{"iviti", "ive", m0},
+		{"biliti", "ble", m0},
+		{"logi", "log", m0}, // --DEPARTURE--
+	}
+
+	// porterStep2Strict is step 2 as published: "abli" instead of "bli", and
+	// no "logi".
+	porterStep2Strict = Step{
+		{"ational", "ate", m0},
+		{"tional", "tion", m0},
+		{"enci", "ence", m0},
+		{"anci", "ance", m0},
+		{"izer", "ize", m0},
+		{"abli", "able", m0},
+		{"alli", "al", m0},
+		{"entli", "ent", m0},
+		{"eli", "e", m0},
+		{"ousli", "ous", m0},
+		{"ization", "ize", m0},
+		{"ation", "ate", m0},
+		{"ator", "ate", m0},
+		{"alism", "al", m0},
+		{"iveness", "ive", m0},
+		{"fulness", "ful", m0},
+		{"ousness", "ous", m0},
+		{"aliti", "al", m0},
+		{"iviti", "ive", m0},
+		{"biliti", "ble", m0},
+	}
+
+	porterStep3 = Step{
+		{"icate", "ic", m0},
+		{"ative", "", m0},
+		{"alize", "al", m0},
+		{"iciti", "ic", m0},
+		{"ical", "ic", m0},
+		{"ful", "", m0},
+		{"ness", "", m0},
+	}
+
+	porterStep4 = Step{
+		{"al", "", m1},
+		{"ance", "", m1},
+		{"ence", "", m1},
+		{"er", "", m1},
+		{"ic", "", m1},
+		{"able", "", m1},
+		{"ible", "", m1},
+		{"ant", "", m1},
+		{"ement", "", m1},
+		{"ment", "", m1},
+		{"ent", "", m1},
+		{"ion", "", And(m1, EndsWithAny("st"))},
+		{"ou", "", m1},
+		{"ism", "", m1},
+		{"ate", "", m1},
+		{"iti", "", m1},
+		{"ous", "", m1},
+		{"ive", "", m1},
+		{"ize", "", m1},
+	}
+)
@@ -0,0 +1,172 @@
+package porter
+
+import (
+	"testing"
+)
+
+func TestConditionNames(t *testing.T) {
+	tests := []struct {
+		cond Condition
+		name string
+	}{
+		{Condition{}, ""},
+		{MeasureGreaterThan(0), "m>0"},
+		{MeasureEquals(1), "m=1"},
+		{ContainsVowel, "*v*"},
+		{EndsDoubleConsonant, "*d"},
+		{EndsCVC, "*o"},
+		{EndsWithAny("l"), "*L"},
+		{And(MeasureGreaterThan(1), EndsWithAny("st")), "m>1 and (*S or *T)"},
+		{Or(MeasureGreaterThan(1), And(MeasureEquals(1), Not(EndsCVC))), "m>1 or (m=1 and not *o)"},
+		{Not(And(EndsDoubleConsonant, EndsWithAny("lsz"))), "not (*d and (*L or *S or *Z))"},
+		{NewCondition("short", func(stem []rune) bool { return len(stem) < 3 }), "short"},
+	}
+	for _, test := range tests {
+		if name := test.cond.String(); name != test.name {
+			t.Errorf("Condition name = %q, expected %q", name, test.name)
+		}
+	}
+}
+
+func TestConditionHolds(t *testing.T) {
+	short := NewCondition("short", func(stem []rune) bool { return len(stem) < 4 })
+	tests := []struct {
+		cond Condition
+		stem string
+		exp  bool
+	}{
+		{Condition{}, "", true},
+		{MeasureGreaterThan(0), "tr", false},
+		{MeasureGreaterThan(0), "trouble", true},
+		{MeasureEquals(1), "oats", true},
+		{MeasureEquals(1), "private", false},
+		{ContainsVowel, "sky", true},
+		{ContainsVowel, "tr", false},
+		{EndsDoubleConsonant, "hopp", true},
+		{EndsDoubleConsonant, "hop", false},
+		{EndsCVC, "fil", true},
+		{EndsCVC, "fix", false},
+		{EndsWithAny("st"), "adopt", true},
+		{EndsWithAny("st"), "opin", false},
+		{And(MeasureGreaterThan(0), short), "oat", true},
+		{And(MeasureGreaterThan(0), short), "oats", false},
+		{Or(short, EndsCVC), "cabins", false},
+		{Or(short, EndsCVC), "sal", true},
+		{Not(short), "oats", true},
+		{Not(And(EndsDoubleConsonant, Not(EndsWithAny("lsz")))), "hiss", true},
+		{Not(And(EndsDoubleConsonant, Not(EndsWithAny("lsz")))), "hopp", false},
+	}
+	for _, test := range tests {
+		if holds := test.cond.Holds([]rune(test.stem)); holds != test.exp {
+			t.Errorf("(%s).Holds(%q) = %t, expected %t", test.cond, test.stem, holds, test.exp)
+		}
+	}
+}
+
+func TestRuleString(t *testing.T) {
+	tests := []struct {
+		rule Rule
+		exp  string
+	}{
+		{Rule{Suffix: "ational", Replacement: "ate", Condition: MeasureGreaterThan(0)}, "(m>0) ATIONAL -> ATE"},
+		{Rule{Suffix: "s"}, "S ->"},
+		{porterStep4[11], "(m>1 and (*S or *T)) ION ->"},
+	}
+	for _, test := range tests {
+		if s := test.rule.String(); s != test.exp {
+			t.Errorf("Rule.String() = %q, expected %q", s, test.exp)
+		}
+	}
+}
+
+func TestStepApply(t *testing.T) {
+	step := Step{
+		{Suffix: "ies", Replacement: "y", Condition: MeasureGreaterThan(0)},
+		{Suffix: "ß", Replacement: "ss"},
+		{Suffix: "s", Replacement: "", Condition: ContainsVowel},
+	}
+	tests := []struct {
+		in, out string
+	}{
+		{"ponies", "pony"},
+		{"ties", "ties"}, // "ies" matches first, but m=0
+		{"cats", "cat"},
+		{"s", "s"}, // the suffix may not be the whole word
+		{"tsks", "tsks"},
+		{"straß", "strass"},
+		{"ß", "ß"},
+		{"", ""},
+	}
+	for _, test := range tests {
+		if out := string(step.Apply([]rune(test.in))); out != test.out {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.in, out, test.out)
+		}
+	}
+}
+
+func TestStepApplySubslice(t *testing.T) {
+	step := Step{
+		{Suffix: "ß", Replacement: "ss"},
+		{Suffix: "ize", Replacement: "ization"},
+		{Suffix: "ies", Replacement: "y"},
+	}
+	tests := []struct {
+		in, out string
+	}{
+		{"straß", "strass"},
+		{"organize", "organization"},
+		{"ponies", "pony"},
+	}
+	for _, test := range tests {
+		buf := []rune(test.in + "|tail")
+		n := len([]rune(test.in))
+		if out := string(step.Apply(buf[:n])); out != test.out {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.in, out, test.out)
+		}
+		if tail := string(buf[n:]); tail != "|tail" {
+			t.Errorf("applying the step to [%s] overwrote the runes after it: [%s]", test.in, tail)
+		}
+	}
+}
+
+// TestStepApplyPorter checks that Apply and the indexed steps of the Porter
+// algorithm agree.
+func TestStepApplyPorter(t *testing.T) {
+	for _, step := range []Step{porterStep2, porterStep2Strict, porterStep3, porterStep4} {
+		x := newSuffixIndex(step)
+		for _, word := range getVoc() {
+			w := newWord([]rune(word))
+			x.apply(&w)
+			exp := string(w.s)
+			if out := string(step.Apply([]rune(word))); out != exp {
+				t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", word, out, exp)
+			}
+		}
+	}
+}
+
+func TestRuleStemmer(t *testing.T) {
+	s := &RuleStemmer{Steps: []Step{
+		{
+			{Suffix: "sses", Replacement: "ss"},
+			{Suffix: "ies", Replacement: "i"},
+			{Suffix: "ss", Replacement: "ss"},
+			{Suffix: "s", Replacement: ""},
+		},
+		porterStep3,
+	}}
+	tests := []struct {
+		in, out string
+	}{
+		{"Caresses", "caress"},
+		{"ponies", "poni"},
+		{"Cats", "cat"},
+		{"hopefulness", "hopeful"},
+		{"hopefuls", "hope"},
+	}
+	for _, test := range tests {
+		if out := s.StemString(test.in); out != test.out {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.in, out, test.out)
+		}
+	}
+}
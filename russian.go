@@ -0,0 +1,220 @@
+package porter
+
+import (
+	"unicode"
+)
+
+// This file implements the Russian stemmer from the Snowball project.  For the
+// algorithm, see:
+//
+// http://snowball.tartarus.org/algorithms/russian/stemmer.html
+//
+// It works on Cyrillic runes, and on the []rune it is given, which it only
+// ever shortens.  Every ending is removed from RV, the region after the first
+// vowel, so the letters before it are never changed.
+
+// The endings of each step.  Only the longest ending that a word ends with is
+// ever considered.  The endings of the first of each pair are only removed
+// after an а or я, which is kept.
+var (
+	russianPerfectiveGerunds  = []string{"в", "вши", "вшись"}
+	russianPerfectiveGerunds2 = []string{"ив", "ивши", "ившись", "ыв", "ывши", "ывшись"}
+	russianAdjectives         = []string{
+		"ее", "ие", "ые", "ое", "ими", "ыми", "ей", "ий", "ый", "ой", "ем", "им",
+		"ым", "ом", "его", "ого", "ему", "ому", "их", "ых", "ую", "юю", "ая",
+		"яя", "ою", "ею",
+	}
+	russianParticiples  = []string{"ем", "нн", "вш", "ющ", "щ"}
+	russianParticiples2 = []string{"ивш", "ывш", "ующ"}
+	russianReflexives   = []string{"ся", "сь"}
+	russianVerbs        = []string{
+		"ла", "на", "ете", "йте", "ли", "й", "л", "ем", "н", "ло", "но", "ет",
+		"ют", "ны", "ть", "ешь", "нно",
+	}
+	russianVerbs2 = []string{
+		"ила", "ыла", "ена", "ейте", "уйте", "ите", "или", "ыли", "ей", "уй",
+		"ил", "ыл", "им", "ым", "ен", "ило", "ыло", "ено", "ят", "ует", "уют",
+		"ит", "ыт", "ены", "ить", "ыть", "ишь", "ую", "ю",
+	}
+	russianNouns = []string{
+		"а", "ев", "ов", "ие", "ье", "е", "иями", "ями", "ами", "еи", "ии",
+		"и", "ией", "ей", "ой", "ий", "й", "иям", "ям", "ием", "ем", "ам",
+		"ом", "о", "у", "ах", "иях", "ях", "ы", "ь", "ию", "ью", "ю", "ия",
+		"ья", "я",
+	}
+	russianDerivationals = []string{"ост", "ость"}
+	russianSuperlatives  = []string{"ейш", "ейше"}
+)
+
+// RussianOptions configures a Russian stemmer.
+type RussianOptions struct {
+	// NormalizeYo replaces each ё with е before stemming, as text usually
+	// writes ё as е.  Otherwise a word with a ё may stem differently from the
+	// same word written with an е.
+	NormalizeYo bool
+}
+
+// Russian is a configurable Russian stemmer.  The zero value is the Snowball
+// Russian algorithm, the same as RussianStemString, RussianStem and
+// RussianStemWithoutLowerCasing.
+//
+// A Russian is safe for concurrent use.
+type Russian struct {
+	opts RussianOptions
+}
+
+var defaultRussian = &Russian{}
+
+// NewRussian returns a Russian stemmer configured with opts.
+func NewRussian(opts RussianOptions) *Russian {
+	return &Russian{opts: opts}
+}
+
+// isRussianVowel returns true if the rune is a vowel.  Unlike isConsonant, it
+// knows the Cyrillic alphabet.
+func isRussianVowel(r rune) bool {
+	switch r {
+	case 'а', 'е', 'и', 'о', 'у', 'ы', 'э', 'ю', 'я':
+		return true
+	}
+	return false
+}
+
+// russianRegions returns the start of the RV and R2 regions.  RV starts after
+// the first vowel, and R2 is found as in the other languages.
+func russianRegions(s []rune) (rv, r2 int) {
+	rv = len(s)
+	for i := 0; i < len(s); i++ {
+		if isRussianVowel(s[i]) {
+			rv = i + 1
+			break
+		}
+	}
+	return rv, regionStart(s, regionStart(s, 0, isRussianVowel), isRussianVowel)
+}
+
+// russianEnding removes the longest ending in RV of either group.  An ending
+// of the first group is only removed after an а or я in RV.  It returns false
+// if no ending was removed.
+func russianEnding(s []rune, rv int, endings, endings2 []string) ([]rune, bool) {
+	ending, i := longestSuffix(s, rv, endings)
+	ending2, j := longestSuffix(s, rv, endings2)
+	switch {
+	case ending2 != "" && j <= i:
+		return s[:j], true
+	case ending != "" && i > rv && (s[i-1] == 'а' || s[i-1] == 'я'):
+		return s[:i], true
+	}
+	return s, false
+}
+
+// russianAdjectival removes an adjective ending in RV, and then a participle
+// ending in RV.  It returns false if there was no adjective ending.
+func russianAdjectival(s []rune, rv int) ([]rune, bool) {
+	ending, i := longestSuffix(s, rv, russianAdjectives)
+	if ending == "" {
+		return s, false
+	}
+	s, _ = russianEnding(s[:i], rv, russianParticiples, russianParticiples2)
+	return s, true
+}
+
+// russianInflection removes a perfective gerund ending in RV, or else a
+// reflexive ending and then an adjectival, verb or noun ending.
+func russianInflection(s []rune, rv int) []rune {
+	s, ok := russianEnding(s, rv, russianPerfectiveGerunds, russianPerfectiveGerunds2)
+	if ok {
+		return s
+	}
+	if ending, i := longestSuffix(s, rv, russianReflexives); ending != "" {
+		s = s[:i]
+	}
+	if s, ok = russianAdjectival(s, rv); ok {
+		return s
+	}
+	if s, ok = russianEnding(s, rv, russianVerbs, russianVerbs2); ok {
+		return s
+	}
+	if ending, i := longestSuffix(s, rv, russianNouns); ending != "" {
+		s = s[:i]
+	}
+	return s
+}
+
+// russianTidyUp removes a superlative ending in RV and then undoubles a final
+// нн in RV, or else undoubles a final нн or removes a final ь in RV.
+func russianTidyUp(s []rune, rv int) []rune {
+	if ending, i := longestSuffix(s, rv, russianSuperlatives); ending != "" {
+		s = s[:i]
+		if i-2 >= rv && s[i-1] == 'н' && s[i-2] == 'н' {
+			s = s[:i-1]
+		}
+		return s
+	}
+	n := len(s)
+	switch {
+	case n-2 >= rv && s[n-1] == 'н' && s[n-2] == 'н':
+		s = s[:n-1]
+	case n-1 >= rv && s[n-1] == 'ь':
+		s = s[:n-1]
+	}
+	return s
+}
+
+// StemString converts a string to a rune array, then stems the result.
+func (r *Russian) StemString(s string) string {
+	ra := []rune(s)
+	ra = r.Stem(ra)
+	return string(ra)
+}
+
+// Stem converts the runes to lower case, then stems the lowercase runes.
+func (r *Russian) Stem(s []rune) []rune {
+	if len(s) == 0 {
+		return s
+	}
+	for i := 0; i < len(s); i++ {
+		s[i] = unicode.ToLower(s[i])
+	}
+	return r.StemWithoutLowerCasing(s)
+}
+
+// StemWithoutLowerCasing applies the stemming assuming that the runes are
+// lowercase.
+func (r *Russian) StemWithoutLowerCasing(s []rune) []rune {
+	if r.opts.NormalizeYo {
+		for i := 0; i < len(s); i++ {
+			if s[i] == 'ё' {
+				s[i] = 'е'
+			}
+		}
+	}
+	rv, r2 := russianRegions(s)
+
+	s = russianInflection(s, rv)
+	if n := len(s); n-1 >= rv && s[n-1] == 'и' {
+		s = s[:n-1]
+	}
+	if ending, i := longestSuffix(s, rv, russianDerivationals); ending != "" && i >= r2 {
+		s = s[:i]
+	}
+	return russianTidyUp(s, rv)
+}
+
+// RussianStemString converts a string to a rune array, then stems the result
+// with the Russian algorithm.
+func RussianStemString(s string) string {
+	return defaultRussian.StemString(s)
+}
+
+// RussianStem converts the runes to lower case, then stems the lowercase runes
+// with the Russian algorithm.
+func RussianStem(s []rune) []rune {
+	return defaultRussian.Stem(s)
+}
+
+// RussianStemWithoutLowerCasing applies the Russian stemming assuming that the
+// runes are lowercase.
+func RussianStemWithoutLowerCasing(s []rune) []rune {
+	return defaultRussian.StemWithoutLowerCasing(s)
+}
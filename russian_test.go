@@ -0,0 +1,108 @@
+package porter
+
+import (
+	"testing"
+)
+
+func TestRussianRegions(t *testing.T) {
+	tests := []struct {
+		s      string
+		rv, r2 string
+	}{
+		{"противоестественном", "тивоестественном", "оестественном"},
+		{"книга", "га", ""},
+		{"я", "", ""},
+		{"вдт", "", ""},
+	}
+	for _, test := range tests {
+		s := []rune(test.s)
+		rv, r2 := russianRegions(s)
+		if string(s[rv:]) != test.rv || string(s[r2:]) != test.r2 {
+			t.Errorf("Did NOT get what was expected for calling russianRegions() on [%s]. Expect RV [%s] and R2 [%s] but got [%s] and [%s]", test.s, test.rv, test.r2, string(s[rv:]), string(s[r2:]))
+		}
+	}
+}
+
+func TestRussianStemString(t *testing.T) {
+	tests := []struct {
+		s, exp string
+	}{
+		{"", ""},
+		{"я", "я"},
+		{"Красивая", "красив"},
+		{"красивейший", "красив"},
+		{"прочитавши", "прочита"},
+		{"прочитав", "прочита"},
+		{"одевшись", "одевш"},
+		{"улыбающийся", "улыба"},
+		{"умывался", "умыва"},
+		{"сделанный", "сдела"},
+		{"сделанная", "сдела"},
+		{"знания", "знан"},
+		{"книгами", "книг"},
+		{"говорили", "говор"},
+		{"стремительность", "стремительн"},
+		{"важнейшее", "важн"},
+		{"длинный", "длин"},
+		{"страннее", "стран"},
+		{"любовь", "любов"},
+		{"ребёнок", "ребёнок"},
+		{"hello", "hello"},
+	}
+	for _, test := range tests {
+		if stem := RussianStemString(test.s); stem != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.s, stem, test.exp)
+		}
+	}
+}
+
+func TestRussianNormalizeYo(t *testing.T) {
+	tests := []struct {
+		s, exp string
+	}{
+		{"ёж", "еж"},
+		{"ребёнок", "ребенок"},
+		{"ребенок", "ребенок"},
+		{"Пришёл", "пришел"},
+	}
+	r := NewRussian(RussianOptions{NormalizeYo: true})
+	for _, test := range tests {
+		if stem := r.StemString(test.s); stem != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.s, stem, test.exp)
+		}
+	}
+}
+
+func TestRussianVocabulary(t *testing.T) {
+	tests := []struct {
+		voc, output string
+		stemmer     Stemmer
+	}{
+		{"russian_voc.txt", "russian_output.txt", defaultRussian},
+		{"russian_voc.txt", "russian_yo_output.txt", NewRussian(RussianOptions{NormalizeYo: true})},
+	}
+	for _, test := range tests {
+		vs := readFields(t, test.voc)
+		os := readFields(t, test.output)
+		if len(vs) != len(os) {
+			t.Fatalf("vocabulary has %d words but output has %d stems", len(vs), len(os))
+		}
+		for i, word := range vs {
+			stem := test.stemmer.StemString(word)
+			if stem != os[i] {
+				t.Errorf("%s: Input: [%s] -> Actual: [%s]. Expected: [%s]", test.output, word, stem, os[i])
+			}
+		}
+	}
+}
+
+func BenchmarkRussianString(b *testing.B) {
+	ss := readFields(b, "russian_voc.txt")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, s := range ss {
+			stem := RussianStemString(s)
+			_ = stem
+		}
+	}
+}
@@ -0,0 +1,576 @@
+package snowball
+
+// command is a Snowball command.  run reports whether it succeeded.  Commands
+// that move the cursor know whether they were written in forward or backward
+// mode, since Snowball fixes the direction when a program is compiled.
+type command interface {
+	run(m *machine) bool
+}
+
+// routine is a routine defined in the program.
+type routine struct {
+	name     string
+	backward bool
+	defined  bool
+	body     command
+}
+
+// grouping is a set of letters.
+type grouping map[rune]bool
+
+// intExpr is an arithmetic expression.
+type intExpr func(m *machine) int
+
+// sequence is commands one after the other: C1 C2 ...
+type sequence []command
+
+func (s sequence) run(m *machine) bool {
+	for _, c := range s {
+		if !c.run(m) {
+			return false
+		}
+	}
+	return true
+}
+
+// or is C1 or C2.
+type or struct {
+	c1, c2   command
+	backward bool
+}
+
+func (o *or) run(m *machine) bool {
+	mark := m.mark(o.backward)
+	if o.c1.run(m) {
+		return true
+	}
+	m.reset(o.backward, mark)
+	return o.c2.run(m)
+}
+
+// and is C1 and C2.
+type and struct {
+	c1, c2   command
+	backward bool
+}
+
+func (a *and) run(m *machine) bool {
+	mark := m.mark(a.backward)
+	if !a.c1.run(m) {
+		return false
+	}
+	m.reset(a.backward, mark)
+	return a.c2.run(m)
+}
+
+// not is not C.
+type not struct {
+	c        command
+	backward bool
+}
+
+func (n *not) run(m *machine) bool {
+	mark := m.mark(n.backward)
+	if n.c.run(m) {
+		return false
+	}
+	m.reset(n.backward, mark)
+	return true
+}
+
+// try is try C.
+type try struct {
+	c        command
+	backward bool
+}
+
+func (t *try) run(m *machine) bool {
+	mark := m.mark(t.backward)
+	if !t.c.run(m) {
+		m.reset(t.backward, mark)
+	}
+	return true
+}
+
+// test is test C.
+type test struct {
+	c        command
+	backward bool
+}
+
+func (t *test) run(m *machine) bool {
+	mark := m.mark(t.backward)
+	if !t.c.run(m) {
+		return false
+	}
+	m.reset(t.backward, mark)
+	return true
+}
+
+// do is do C.
+type do struct {
+	c        command
+	backward bool
+}
+
+func (d *do) run(m *machine) bool {
+	mark := m.mark(d.backward)
+	d.c.run(m)
+	m.reset(d.backward, mark)
+	return true
+}
+
+// fail is fail C.
+type fail struct {
+	c command
+}
+
+func (f *fail) run(m *machine) bool {
+	f.c.run(m)
+	return false
+}
+
+// goTo is goto C, or gopast C if past is true.
+type goTo struct {
+	c        command
+	past     bool
+	backward bool
+}
+
+func (g *goTo) run(m *machine) bool {
+	for {
+		mark := m.mark(g.backward)
+		if g.c.run(m) {
+			if !g.past {
+				m.reset(g.backward, mark)
+			}
+			return true
+		}
+		m.reset(g.backward, mark)
+		if !m.next(g.backward) {
+			return false
+		}
+	}
+}
+
+// repeat is repeat C, or atleast n C.
+type repeat struct {
+	c        command
+	atLeast  intExpr
+	backward bool
+}
+
+func (r *repeat) run(m *machine) bool {
+	if r.atLeast != nil {
+		for i := r.atLeast(m); i > 0; i-- {
+			if !r.c.run(m) {
+				return false
+			}
+		}
+	}
+	for {
+		mark := m.mark(r.backward)
+		if !r.c.run(m) {
+			m.reset(r.backward, mark)
+			return true
+		}
+	}
+}
+
+// loop is loop n C.
+type loop struct {
+	c command
+	n intExpr
+}
+
+func (l *loop) run(m *machine) bool {
+	for i := l.n(m); i > 0; i-- {
+		if !l.c.run(m) {
+			return false
+		}
+	}
+	return true
+}
+
+// backwards is backwards C, which runs C from the limit towards the cursor.
+type backwards struct {
+	c command
+}
+
+func (b *backwards) run(m *machine) bool {
+	lb := m.lb
+	m.lb, m.c = m.c, m.l
+	if !b.c.run(m) {
+		m.lb = lb
+		return false
+	}
+	m.c, m.lb = m.lb, lb
+	return true
+}
+
+// setlimit is setlimit C1 for C2.
+type setlimit struct {
+	c1, c2   command
+	backward bool
+}
+
+func (s *setlimit) run(m *machine) bool {
+	mark := m.mark(s.backward)
+	if !s.c1.run(m) {
+		return false
+	}
+	if s.backward {
+		lb := m.lb
+		m.lb = m.c
+		m.reset(true, mark)
+		ok := s.c2.run(m)
+		m.lb = lb
+		return ok
+	}
+	// The limit is kept from the end of the word, which C2 may change.
+	l := m.l - m.c
+	m.l = m.c
+	m.reset(false, mark)
+	ok := s.c2.run(m)
+	m.l += l
+	return ok
+}
+
+// call calls a routine.
+type call struct {
+	r *routine
+}
+
+func (c *call) run(m *machine) bool {
+	return c.r.body.run(m)
+}
+
+// literal matches a string at the cursor.
+type literal struct {
+	s        []rune
+	backward bool
+}
+
+func (l *literal) run(m *machine) bool {
+	return m.eq(l.s, l.backward)
+}
+
+// stringVar matches the value of a string variable at the cursor.
+type stringVar struct {
+	v        int
+	backward bool
+}
+
+func (s *stringVar) run(m *machine) bool {
+	return m.eq(m.strs[s.v], s.backward)
+}
+
+// inGrouping matches a letter of a grouping at the cursor, or with non, a
+// letter that is not in it.
+type inGrouping struct {
+	g        grouping
+	non      bool
+	backward bool
+}
+
+func (g *inGrouping) run(m *machine) bool {
+	i := m.c
+	if g.backward {
+		if m.c <= m.lb {
+			return false
+		}
+		i--
+	} else if m.c >= m.l {
+		return false
+	}
+	if g.g[m.s[i]] == g.non {
+		return false
+	}
+	return m.next(g.backward)
+}
+
+// next is next.
+type next struct {
+	backward bool
+}
+
+func (n *next) run(m *machine) bool {
+	return m.next(n.backward)
+}
+
+// hop is hop n.
+type hop struct {
+	n        intExpr
+	backward bool
+}
+
+func (h *hop) run(m *machine) bool {
+	n := h.n(m)
+	if h.backward {
+		if c := m.c - n; n >= 0 && c >= m.lb {
+			m.c = c
+			return true
+		}
+		return false
+	}
+	if c := m.c + n; n >= 0 && c <= m.l {
+		m.c = c
+		return true
+	}
+	return false
+}
+
+// setmark is setmark x.
+type setmark struct {
+	v int
+}
+
+func (s *setmark) run(m *machine) bool {
+	m.ints[s.v] = m.c
+	return true
+}
+
+// tomark is tomark n, or atmark n if at is true.
+type tomark struct {
+	n        intExpr
+	at       bool
+	backward bool
+}
+
+func (t *tomark) run(m *machine) bool {
+	n := t.n(m)
+	if t.at {
+		return m.c == n
+	}
+	if t.backward && m.c < n || !t.backward && m.c > n {
+		return false
+	}
+	m.c = n
+	return true
+}
+
+// tolimit is tolimit, or atlimit if at is true.
+type tolimit struct {
+	at       bool
+	backward bool
+}
+
+func (t *tolimit) run(m *machine) bool {
+	l := m.l
+	if t.backward {
+		l = m.lb
+	}
+	if t.at {
+		return m.c == l
+	}
+	m.c = l
+	return true
+}
+
+// bra is [ in forward mode, and ] in backward mode.  It sets the start of the
+// slice.
+type bra struct{}
+
+func (bra) run(m *machine) bool {
+	m.bra = m.c
+	return true
+}
+
+// ket is ] in forward mode, and [ in backward mode.  It sets the end of the
+// slice.
+type ket struct{}
+
+func (ket) run(m *machine) bool {
+	m.ket = m.c
+	return true
+}
+
+// sliceFrom is <- S, or delete if s is empty and v is negative.
+type sliceFrom struct {
+	s []rune
+	v int // a string variable, or -1 for s
+}
+
+func (s *sliceFrom) run(m *machine) bool {
+	if s.v >= 0 {
+		return m.sliceFrom(m.strs[s.v])
+	}
+	return m.sliceFrom(s.s)
+}
+
+// sliceTo is -> s.
+type sliceTo struct {
+	v int
+}
+
+func (s *sliceTo) run(m *machine) bool {
+	if m.bra < 0 || m.bra > m.ket || m.ket > m.l {
+		return false
+	}
+	m.strs[s.v] = append(m.strs[s.v][:0], m.s[m.bra:m.ket]...)
+	return true
+}
+
+// insert is insert S (or <+ S), or attach S.  Insert leaves the cursor after
+// the string in forward mode, and attach leaves it before.
+type insert struct {
+	s     []rune
+	v     int // a string variable, or -1 for s
+	after bool
+}
+
+func (i *insert) run(m *machine) bool {
+	s := i.s
+	if i.v >= 0 {
+		s = m.strs[i.v]
+	}
+	m.insert(s, i.after)
+	return true
+}
+
+// setBool is set b, or unset b.
+type setBool struct {
+	v   int
+	val bool
+}
+
+func (s *setBool) run(m *machine) bool {
+	m.bools[s.v] = s.val
+	return true
+}
+
+// testBool is b as a command.
+type testBool struct {
+	v int
+}
+
+func (t *testBool) run(m *machine) bool {
+	return m.bools[t.v]
+}
+
+// constant is true or false.
+type constant bool
+
+func (c constant) run(m *machine) bool {
+	return bool(c)
+}
+
+// assign is $x = n, $x += n, and so on.
+type assign struct {
+	v  int
+	op string
+	n  intExpr
+}
+
+func (a *assign) run(m *machine) bool {
+	n := a.n(m)
+	switch a.op {
+	case "=":
+		m.ints[a.v] = n
+	case "+=":
+		m.ints[a.v] += n
+	case "-=":
+		m.ints[a.v] -= n
+	case "*=":
+		m.ints[a.v] *= n
+	case "/=":
+		if n == 0 {
+			return false
+		}
+		m.ints[a.v] /= n
+	}
+	return true
+}
+
+// compare is $x < n, $(n1 == n2), and so on.
+type compare struct {
+	n1, n2 intExpr
+	op     string
+}
+
+func (c *compare) run(m *machine) bool {
+	n1, n2 := c.n1(m), c.n2(m)
+	switch c.op {
+	case "==":
+		return n1 == n2
+	case "!=":
+		return n1 != n2
+	case "<":
+		return n1 < n2
+	case "<=":
+		return n1 <= n2
+	case ">":
+		return n1 > n2
+	case ">=":
+		return n1 >= n2
+	}
+	return false
+}
+
+// amongString is one of the strings of an among, and the routine that must
+// also succeed for it to match.
+type amongString struct {
+	s         []rune
+	condition *routine
+	result    int // the command to run, from 1, or 0 for none
+}
+
+// among is among(...), which runs the command that follows the longest of
+// its strings that matches at the cursor.  If it has a substring, the
+// substring matches the string, and among only runs the command.
+type among struct {
+	id        int
+	strings   []amongString // longest first
+	commands  []command
+	substring bool
+	backward  bool
+}
+
+// find moves the cursor over the longest string that matches, and returns
+// its index, from 1, or 0 if none match.
+func (a *among) find(m *machine) int {
+	c := m.c
+	for i, s := range a.strings {
+		if !m.eq(s.s, a.backward) {
+			continue
+		}
+		if s.condition != nil {
+			after := m.c
+			ok := s.condition.body.run(m)
+			m.c = after
+			if !ok {
+				m.c = c
+				continue
+			}
+		}
+		return i + 1
+	}
+	return 0
+}
+
+func (a *among) run(m *machine) bool {
+	found := m.found[a.id]
+	if !a.substring {
+		if found = a.find(m); found == 0 {
+			return false
+		}
+	}
+	if found == 0 {
+		return false
+	}
+	result := a.strings[found-1].result
+	if result == 0 {
+		return true
+	}
+	return a.commands[result-1].run(m)
+}
+
+// substring is substring, which finds the string of an among.
+type substring struct {
+	a *among
+}
+
+func (s *substring) run(m *machine) bool {
+	m.found[s.a.id] = s.a.find(m)
+	return m.found[s.a.id] != 0
+}
@@ -0,0 +1,133 @@
+package snowball
+
+import (
+	"strings"
+	"testing"
+)
+
+// header declares the names that the commands of TestCommands use.
+const header = `
+integers ( x y )
+booleans ( b )
+strings ( s )
+externals ( stem )
+groupings ( v )
+define v 'aeiou'
+`
+
+func TestCommands(t *testing.T) {
+	tests := []struct {
+		stem, s, exp string
+	}{
+		{"['ab'] <- 'x'", "abc", "xc"},
+		{"['ab'] delete", "abc", "c"},
+		{"['ab'] <- 'xyz' 'c' tolimit atlimit", "abc", "xyzc"},
+		{"['x'] delete", "abc", "abc"},
+		{"hop 1 insert 'X' 'b'", "abc", "aXbc"},
+		{"hop 1 <+ 'X' 'b'", "abc", "aXbc"},
+		{"hop 1 attach 'X' 'X'", "abc", "aXbc"},
+		{"backwards ( next <+ 'X' 'b' )", "abc", "abXc"},
+		{"backwards ( next attach 'X' 'X' )", "abc", "abXc"},
+		{"'a' or 'b' [next] delete", "bcd", "bd"},
+		{"'a' and 'b' [next] delete", "abc", "abc"},
+		{"'a' and hop 2 [next] delete", "abcd", "abd"},
+		{"not 'x' test 'a' ['a'] delete", "ab", "b"},
+		{"not 'a' ['a'] delete", "ab", "ab"},
+		{"try 'x' ['a'] delete", "ab", "b"},
+		{"try fail ([next] delete) [next] delete", "abc", "c"},
+		{"do ('a' 'x') ['a'] delete", "ab", "b"},
+		{"goto 'c' [next] delete", "abcd", "abd"},
+		{"gopast 'c' [next] delete", "abcd", "abc"},
+		{"repeat ( gopast ( [v] ) <- '*' )", "banana", "b*n*n*"},
+		{"repeat ( gopast ( [non-v] ) <- '*' )", "banana", "*a*a*a"},
+		{"loop 2 ( ['a'] <- 'b' )", "aaa", "bba"},
+		{"atleast 2 ( ['a'] <- 'b' )", "aaa", "bbb"},
+		{"atleast 2 ( ['a'] <- 'b' ) <- 'c'", "abb", "bbb"},
+		{"hop 2 [next] delete", "abcd", "abd"},
+		{"hop 5 [next] delete", "abcd", "abcd"},
+		{"next next [next] delete", "abcd", "abd"},
+		{"tomark 2 atmark 2 [next] delete", "abcd", "abd"},
+		{"hop 3 tomark 1 [next] delete", "abcd", "abcd"},
+		{"tolimit atlimit [] <- 'x'", "ab", "abx"},
+		{"hop 1 setmark x $x == 1 [tolimit] delete", "abc", "a"},
+		{"$x = 2 $x += 3 $x *= 2 $x -= 4 $x /= 3 $(x == 2) hop x [next] delete", "abcd", "abd"},
+		{"$y = (1 + 2) * 3 - -1 $(y == 10) $(y != 9) $y > 9 $y >= 10 $y < 11 $y <= 10 [next] delete", "ab", "b"},
+		{"$x = size - 1 $y = len / 2 hop x $(cursor == limit - 1) $(y == 2) [next] delete", "abcd", "abc"},
+		{"$x = maxint $y = minint $(x > y) [next] delete", "ab", "b"},
+		{"$x = 1 $x /= 0 [next] delete", "ab", "ab"},
+		{"set b b unset b not b [next] delete", "ab", "b"},
+		{"[hop 2] -> s tolimit insert s", "abc", "abcab"},
+		{"[hop 2] -> s tolimit [] <- s s", "abc", "abcab"},
+		{"setlimit tomark 3 for repeat ( gopast ( ['b'] ) <- 'B' )", "abcabc", "aBcabc"},
+		{"setlimit tomark 3 for ( tolimit ) atmark 3", "abcabc", "abcabc"},
+		{"setlimit tomark 3 for ( tolimit [] <- 'xx' ) tolimit atmark 8 [] <- 'y'", "abcabc", "abcxxabcy"},
+		{"backwards ( setlimit tomark 2 for repeat ( gopast ( ['a'] ) <- 'A' ) )", "abab", "abAb"},
+		{"backwards ( repeat ( gopast ( ['a'] ) <- 'A' ) )", "abab", "AbAb"},
+		{"backwards ( hop 2 [next] delete )", "abcd", "acd"},
+		{"backwards ( tolimit atlimit ) atlimit [next] delete", "ab", "ab"},
+		{"backwards ( tolimit atlimit ) [next] delete", "ab", "b"},
+		{"true [next] delete", "ab", "b"},
+		{"false [next] delete", "ab", "ab"},
+		{"? [next] delete", "ab", "b"},
+	}
+	for _, test := range tests {
+		p, err := Read(strings.NewReader(header + "define stem as (" + test.stem + ")"))
+		if err != nil {
+			t.Errorf("%s: %v", test.stem, err)
+			continue
+		}
+		if stem := p.StemString(test.s); stem != test.exp {
+			t.Errorf("%s: Input: [%s] -> Actual: [%s]. Expected: [%s]", test.stem, test.s, stem, test.exp)
+		}
+	}
+}
+
+func TestAmong(t *testing.T) {
+	p, err := Read(strings.NewReader(`
+routines ( two last )
+externals ( stem )
+groupings ( v x )
+define v 'aeiouy'
+define x v - 'y' + 'x'
+define two as hop 2
+backwardmode (
+    define last as (
+        [substring] x among (
+            'a' 'e' (<- 'V')
+            'x' (delete)
+            'ax'
+        )
+    )
+)
+define stem as (
+    [substring] among (
+        'a' two (<-'A')
+        'ab' two (<-'AB')
+        'abc' (<-'ABC')
+        'z'
+    )
+    backwards last
+)
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		s, exp string
+	}{
+		{"abcd", "ABCd"},
+		{"abxyz", "ABxyz"},
+		{"abx", "Abx"},
+		{"ab", "ab"},
+		{"zoa", "zoV"},
+		{"zox", "zo"},
+		{"zaax", "zaax"},
+		{"zay", "zay"},
+		{"q", "q"},
+	}
+	for _, test := range tests {
+		if stem := p.StemString(test.s); stem != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.s, stem, test.exp)
+		}
+	}
+}
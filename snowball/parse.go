@@ -0,0 +1,1059 @@
+package snowball
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// The kinds of token.
+const (
+	tEOF = iota
+	tName
+	tString
+	tNumber
+	tSymbol
+)
+
+// token is a token of Snowball source.
+type token struct {
+	kind int
+	text string // the name, number or symbol
+	s    []rune // the runes of a string, after escapes
+	line int
+}
+
+// symbols are the symbols of Snowball, longest first.
+var symbols = []string{
+	"<-", "<+", "->", "=>", "==", "!=", "<=", ">=", "+=", "-=", "*=", "/=",
+	"(", ")", "[", "]", "$", "=", "<", ">", "+", "-", "*", "/", "?",
+}
+
+// lexer splits Snowball source into tokens.  It is driven by the parser,
+// since stringescapes and stringdef change how later strings are read.
+type lexer struct {
+	src     []rune
+	i       int
+	line    int
+	escapes [2]rune // the characters that start and end an escape, if any
+	defs    map[string][]rune
+}
+
+// skip skips space and comments.
+func (l *lexer) skip() error {
+	for l.i < len(l.src) {
+		switch r := l.src[l.i]; {
+		case r == '\n':
+			l.line++
+			l.i++
+		case unicode.IsSpace(r):
+			l.i++
+		case r == '/' && l.i+1 < len(l.src) && l.src[l.i+1] == '/':
+			for l.i < len(l.src) && l.src[l.i] != '\n' {
+				l.i++
+			}
+		case r == '/' && l.i+1 < len(l.src) && l.src[l.i+1] == '*':
+			line := l.line
+			l.i += 2
+			for ; l.i+1 < len(l.src) && !(l.src[l.i] == '*' && l.src[l.i+1] == '/'); l.i++ {
+				if l.src[l.i] == '\n' {
+					l.line++
+				}
+			}
+			if l.i+1 >= len(l.src) {
+				return fmt.Errorf("line %d: comment is not closed", line)
+			}
+			l.i += 2
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// isNameRune reports whether r can be part of a name.
+func isNameRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// next returns the next token.
+func (l *lexer) next() (token, error) {
+	if err := l.skip(); err != nil {
+		return token{}, err
+	}
+	t := token{line: l.line}
+	if l.i >= len(l.src) {
+		return t, nil
+	}
+	start := l.i
+	switch r := l.src[l.i]; {
+	case unicode.IsDigit(r):
+		for l.i < len(l.src) && unicode.IsDigit(l.src[l.i]) {
+			l.i++
+		}
+		t.kind, t.text = tNumber, string(l.src[start:l.i])
+	case isNameRune(r):
+		for l.i < len(l.src) && isNameRune(l.src[l.i]) {
+			l.i++
+		}
+		t.kind, t.text = tName, string(l.src[start:l.i])
+	case r == '\'':
+		s, err := l.str()
+		if err != nil {
+			return t, err
+		}
+		t.kind, t.s = tString, s
+	default:
+		for _, sym := range symbols {
+			if strings.HasPrefix(string(l.src[l.i:min(l.i+2, len(l.src))]), sym) {
+				l.i += len(sym)
+				t.kind, t.text = tSymbol, sym
+				return t, nil
+			}
+		}
+		return t, fmt.Errorf("line %d: unexpected %q", l.line, r)
+	}
+	return t, nil
+}
+
+// min returns the smaller of a and b.
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// str reads a string in quotes, replacing its escapes.
+func (l *lexer) str() ([]rune, error) {
+	line := l.line
+	var s []rune
+	for l.i++; l.i < len(l.src); l.i++ {
+		r := l.src[l.i]
+		switch {
+		case r == '\'':
+			l.i++
+			return s, nil
+		case r == '\n':
+			return nil, fmt.Errorf("line %d: string is not closed", line)
+		case l.escapes[0] != 0 && r == l.escapes[0]:
+			end := l.i + 1
+			for end < len(l.src) && l.src[end] != l.escapes[1] && l.src[end] != '\n' {
+				end++
+			}
+			if end >= len(l.src) || l.src[end] != l.escapes[1] {
+				return nil, fmt.Errorf("line %d: escape is not closed", line)
+			}
+			name := string(l.src[l.i+1 : end])
+			switch def, ok := l.defs[name]; {
+			case name == "'" || name == string(l.escapes[0]):
+				s = append(s, []rune(name)...)
+			case ok:
+				s = append(s, def...)
+			default:
+				return nil, fmt.Errorf("line %d: unknown escape %q", line, name)
+			}
+			l.i = end
+		default:
+			s = append(s, r)
+		}
+	}
+	return nil, fmt.Errorf("line %d: string is not closed", line)
+}
+
+// raw returns the next word of the source, up to space, without reading it
+// as a token.
+func (l *lexer) raw() (string, error) {
+	if err := l.skip(); err != nil {
+		return "", err
+	}
+	start := l.i
+	for l.i < len(l.src) && !unicode.IsSpace(l.src[l.i]) {
+		l.i++
+	}
+	if start == l.i {
+		return "", fmt.Errorf("line %d: unexpected end of program", l.line)
+	}
+	return string(l.src[start:l.i]), nil
+}
+
+// The kinds of name.
+const (
+	kRoutine = iota
+	kExternal
+	kGrouping
+	kInteger
+	kBoolean
+	kString
+)
+
+// declaration is a declared name.
+type declaration struct {
+	kind     int
+	index    int // of the variable
+	routine  *routine
+	grouping grouping
+}
+
+// parser parses a Snowball program.
+type parser struct {
+	lex      lexer
+	tok      token
+	names    map[string]*declaration
+	backward bool
+	// pending is the among of the last substring, which the next among
+	// in the routine belongs to.
+	pending *among
+	prog    *Program
+	calls   []callSite
+}
+
+// callSite is a call of a routine, which is checked once every routine is
+// defined.
+type callSite struct {
+	r        *routine
+	backward bool
+	line     int
+}
+
+// parse parses a Snowball program.
+func parse(src string) (*Program, error) {
+	p := &parser{
+		lex:   lexer{src: []rune(src), line: 1, defs: make(map[string][]rune)},
+		names: make(map[string]*declaration),
+		prog:  &Program{},
+	}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	for p.tok.kind != tEOF {
+		if err := p.declaration(); err != nil {
+			return nil, err
+		}
+	}
+	for name, d := range p.names {
+		if (d.kind == kRoutine || d.kind == kExternal) && !d.routine.defined {
+			return nil, fmt.Errorf("routine %s is declared but not defined", name)
+		}
+	}
+	for _, c := range p.calls {
+		if c.r.backward != c.backward {
+			return nil, fmt.Errorf("line %d: routine %s is called in the wrong mode", c.line, c.r.name)
+		}
+	}
+	d, ok := p.names["stem"]
+	if !ok || d.kind != kExternal {
+		return nil, fmt.Errorf("program has no external routine stem")
+	}
+	if d.routine.backward {
+		return nil, fmt.Errorf("external routine stem is defined in backward mode")
+	}
+	p.prog.stem = d.routine
+	return p.prog, nil
+}
+
+// next reads the next token.
+func (p *parser) next() error {
+	t, err := p.lex.next()
+	p.tok = t
+	return err
+}
+
+// errorf returns an error at the current token.
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", p.tok.line, fmt.Sprintf(format, args...))
+}
+
+// is reports whether the current token is a name or symbol.
+func (p *parser) is(text string) bool {
+	return (p.tok.kind == tName || p.tok.kind == tSymbol) && p.tok.text == text
+}
+
+// expect reads a name or symbol.
+func (p *parser) expect(text string) error {
+	if !p.is(text) {
+		return p.errorf("expected %s", text)
+	}
+	return p.next()
+}
+
+// name reads a name.
+func (p *parser) name() (string, error) {
+	if p.tok.kind != tName {
+		return "", p.errorf("expected a name")
+	}
+	name := p.tok.text
+	return name, p.next()
+}
+
+// lookup reads a declared name of one of the kinds.
+func (p *parser) lookup(kinds ...int) (*declaration, error) {
+	if p.tok.kind != tName {
+		return nil, p.errorf("expected a name")
+	}
+	d, ok := p.names[p.tok.text]
+	if !ok {
+		return nil, p.errorf("%s is not declared", p.tok.text)
+	}
+	for _, k := range kinds {
+		if d.kind == k {
+			return d, p.next()
+		}
+	}
+	return nil, p.errorf("%s is the wrong kind of name here", p.tok.text)
+}
+
+// declaration parses a declaration, definition, or backwardmode section.
+func (p *parser) declaration() error {
+	if p.tok.kind != tName {
+		return p.errorf("expected a declaration")
+	}
+	switch p.tok.text {
+	case "integers", "booleans", "strings", "routines", "externals", "groupings":
+		return p.declare()
+	case "define":
+		return p.define()
+	case "backwardmode":
+		if err := p.next(); err != nil {
+			return err
+		}
+		if err := p.expect("("); err != nil {
+			return err
+		}
+		p.backward = true
+		for !p.is(")") {
+			if p.tok.kind == tEOF {
+				return p.errorf("backwardmode is not closed")
+			}
+			if err := p.declaration(); err != nil {
+				return err
+			}
+		}
+		p.backward = false
+		return p.next()
+	case "stringescapes":
+		open, err := p.lex.raw()
+		if err != nil {
+			return err
+		}
+		runes := []rune(open)
+		if len(runes) == 1 {
+			var close string
+			if close, err = p.lex.raw(); err != nil {
+				return err
+			}
+			runes = append(runes, []rune(close)...)
+		}
+		if len(runes) != 2 {
+			return p.errorf("stringescapes needs two characters")
+		}
+		p.lex.escapes = [2]rune{runes[0], runes[1]}
+		return p.next()
+	case "stringdef":
+		name, err := p.lex.raw()
+		if err != nil {
+			return err
+		}
+		if err := p.next(); err != nil {
+			return err
+		}
+		base := 0
+		switch {
+		case p.is("hex"):
+			base = 16
+		case p.is("decimal"):
+			base = 10
+		}
+		if base != 0 {
+			if err := p.next(); err != nil {
+				return err
+			}
+		}
+		if p.tok.kind != tString {
+			return p.errorf("expected a string")
+		}
+		s := p.tok.s
+		if base != 0 {
+			s = nil
+			for _, f := range strings.Fields(string(p.tok.s)) {
+				n, err := strconv.ParseInt(f, base, 32)
+				if err != nil {
+					return p.errorf("bad character code %q", f)
+				}
+				s = append(s, rune(n))
+			}
+		}
+		p.lex.defs[name] = s
+		return p.next()
+	}
+	return p.errorf("unexpected %s", p.tok.text)
+}
+
+// declare parses a declaration of names.
+func (p *parser) declare() error {
+	kind := map[string]int{
+		"integers":  kInteger,
+		"booleans":  kBoolean,
+		"strings":   kString,
+		"routines":  kRoutine,
+		"externals": kExternal,
+		"groupings": kGrouping,
+	}[p.tok.text]
+	if err := p.next(); err != nil {
+		return err
+	}
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	for !p.is(")") {
+		line := p.tok.line
+		name, err := p.name()
+		if err != nil {
+			return err
+		}
+		if _, dup := p.names[name]; dup {
+			return fmt.Errorf("line %d: %s is declared twice", line, name)
+		}
+		d := &declaration{kind: kind}
+		switch kind {
+		case kInteger:
+			d.index = p.prog.ints
+			p.prog.ints++
+		case kBoolean:
+			d.index = p.prog.bools
+			p.prog.bools++
+		case kString:
+			d.index = p.prog.strs
+			p.prog.strs++
+		case kRoutine, kExternal:
+			d.routine = &routine{name: name}
+		}
+		p.names[name] = d
+	}
+	return p.next()
+}
+
+// define parses the definition of a routine or grouping.
+func (p *parser) define() error {
+	if err := p.next(); err != nil {
+		return err
+	}
+	line := p.tok.line
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	d, ok := p.names[name]
+	if !ok {
+		return fmt.Errorf("line %d: %s is not declared", line, name)
+	}
+	switch d.kind {
+	case kGrouping:
+		if d.grouping != nil {
+			return fmt.Errorf("line %d: %s is defined twice", line, name)
+		}
+		g, err := p.grouping()
+		if err != nil {
+			return err
+		}
+		d.grouping = g
+		return nil
+	case kRoutine, kExternal:
+		r := d.routine
+		if r.defined {
+			return fmt.Errorf("line %d: %s is defined twice", line, name)
+		}
+		if err := p.expect("as"); err != nil {
+			return err
+		}
+		r.defined, r.backward = true, p.backward
+		p.pending = nil
+		if r.body, err = p.command(); err != nil {
+			return err
+		}
+		if p.pending != nil {
+			return fmt.Errorf("line %d: substring in %s has no among", line, name)
+		}
+		return nil
+	}
+	return fmt.Errorf("line %d: %s cannot be defined", line, name)
+}
+
+// grouping parses the letters of a grouping: strings and groupings, joined
+// by + and -.
+func (p *parser) grouping() (grouping, error) {
+	g := make(grouping)
+	add := true
+	for {
+		var letters []rune
+		switch p.tok.kind {
+		case tString:
+			letters = p.tok.s
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+		case tName:
+			d, err := p.lookup(kGrouping)
+			if err != nil {
+				return nil, err
+			}
+			if d.grouping == nil {
+				return nil, p.errorf("grouping is used before it is defined")
+			}
+			for r := range d.grouping {
+				letters = append(letters, r)
+			}
+		default:
+			return nil, p.errorf("expected a string or grouping")
+		}
+		for _, r := range letters {
+			if add {
+				g[r] = true
+			} else {
+				delete(g, r)
+			}
+		}
+		switch {
+		case p.is("+"):
+			add = true
+		case p.is("-"):
+			add = false
+		default:
+			return g, nil
+		}
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// command parses a command: unary commands joined by or and and.
+func (p *parser) command() (command, error) {
+	c, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.is("or") || p.is("and") {
+		op := p.tok.text
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		c2, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		if op == "or" {
+			c = &or{c, c2, p.backward}
+		} else {
+			c = &and{c, c2, p.backward}
+		}
+	}
+	return c, nil
+}
+
+// unary parses a command with its prefix operators.
+func (p *parser) unary() (command, error) {
+	if p.tok.kind == tEOF {
+		return nil, p.errorf("unexpected end of program")
+	}
+	if p.tok.kind == tName {
+		switch p.tok.text {
+		case "not", "try", "test", "do", "fail", "goto", "gopast", "repeat", "backwards":
+			op := p.tok.text
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			if op == "backwards" {
+				if p.backward {
+					return nil, p.errorf("backwards in backward mode")
+				}
+				p.backward = true
+				defer func() { p.backward = false }()
+			}
+			c, err := p.unary()
+			if err != nil {
+				return nil, err
+			}
+			switch op {
+			case "not":
+				return &not{c, p.backward}, nil
+			case "try":
+				return &try{c, p.backward}, nil
+			case "test":
+				return &test{c, p.backward}, nil
+			case "do":
+				return &do{c, p.backward}, nil
+			case "fail":
+				return &fail{c}, nil
+			case "goto":
+				return &goTo{c, false, p.backward}, nil
+			case "gopast":
+				return &goTo{c, true, p.backward}, nil
+			case "repeat":
+				return &repeat{c, nil, p.backward}, nil
+			}
+			return &backwards{c}, nil
+		case "loop", "atleast":
+			op := p.tok.text
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			n, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			c, err := p.unary()
+			if err != nil {
+				return nil, err
+			}
+			if op == "loop" {
+				return &loop{c, n}, nil
+			}
+			return &repeat{c, n, p.backward}, nil
+		case "setlimit":
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			c1, err := p.unary()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("for"); err != nil {
+				return nil, err
+			}
+			c2, err := p.unary()
+			if err != nil {
+				return nil, err
+			}
+			return &setlimit{c1, c2, p.backward}, nil
+		}
+	}
+	return p.primary()
+}
+
+// primary parses a command that is not made of other commands by an
+// operator.
+func (p *parser) primary() (command, error) {
+	line := p.tok.line
+	switch p.tok.kind {
+	case tString:
+		s := p.tok.s
+		return &literal{s, p.backward}, p.next()
+	case tSymbol:
+		switch p.tok.text {
+		case "(":
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			var s sequence
+			for !p.is(")") {
+				c, err := p.command()
+				if err != nil {
+					return nil, err
+				}
+				s = append(s, c)
+			}
+			if len(s) == 1 {
+				return s[0], p.next()
+			}
+			return s, p.next()
+		case "[":
+			if p.backward {
+				return ket{}, p.next()
+			}
+			return bra{}, p.next()
+		case "]":
+			if p.backward {
+				return bra{}, p.next()
+			}
+			return ket{}, p.next()
+		case "<-", "<+":
+			op := p.tok.text
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			s, v, err := p.stringOperand()
+			if err != nil {
+				return nil, err
+			}
+			if op == "<-" {
+				return &sliceFrom{s, v}, nil
+			}
+			return &insert{s, v, !p.backward}, nil
+		case "->":
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			d, err := p.lookup(kString)
+			if err != nil {
+				return nil, err
+			}
+			return &sliceTo{d.index}, nil
+		case "$":
+			return p.integerCommand()
+		case "?":
+			return constant(true), p.next()
+		}
+		return nil, p.errorf("unexpected %s", p.tok.text)
+	case tName:
+	default:
+		return nil, p.errorf("expected a command")
+	}
+
+	switch p.tok.text {
+	case "true", "false":
+		c := constant(p.tok.text == "true")
+		return c, p.next()
+	case "next":
+		return &next{p.backward}, p.next()
+	case "delete":
+		return &sliceFrom{nil, -1}, p.next()
+	case "insert", "attach":
+		after := p.tok.text == "insert" != p.backward
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		s, v, err := p.stringOperand()
+		if err != nil {
+			return nil, err
+		}
+		return &insert{s, v, after}, nil
+	case "hop", "tomark", "atmark":
+		op := p.tok.text
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		n, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if op == "hop" {
+			return &hop{n, p.backward}, nil
+		}
+		return &tomark{n, op == "atmark", p.backward}, nil
+	case "tolimit", "atlimit":
+		return &tolimit{p.tok.text == "atlimit", p.backward}, p.next()
+	case "setmark":
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		d, err := p.lookup(kInteger)
+		if err != nil {
+			return nil, err
+		}
+		return &setmark{d.index}, nil
+	case "set", "unset":
+		val := p.tok.text == "set"
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		d, err := p.lookup(kBoolean)
+		if err != nil {
+			return nil, err
+		}
+		return &setBool{d.index, val}, nil
+	case "non":
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		if p.is("-") {
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+		}
+		d, err := p.lookup(kGrouping)
+		if err != nil {
+			return nil, err
+		}
+		return &inGrouping{d.grouping, true, p.backward}, nil
+	case "substring":
+		if p.pending != nil {
+			return nil, p.errorf("substring has no among")
+		}
+		p.pending = &among{id: p.prog.amongs, substring: true, backward: p.backward}
+		p.prog.amongs++
+		return &substring{p.pending}, p.next()
+	case "among":
+		return p.among()
+	case "reverse", "get", "sizeof", "lenof":
+		return nil, p.errorf("%s is not supported", p.tok.text)
+	}
+
+	d, err := p.lookup(kRoutine, kExternal, kGrouping, kBoolean, kString)
+	if err != nil {
+		return nil, err
+	}
+	switch d.kind {
+	case kGrouping:
+		if d.grouping == nil {
+			return nil, fmt.Errorf("line %d: grouping is used before it is defined", line)
+		}
+		return &inGrouping{d.grouping, false, p.backward}, nil
+	case kBoolean:
+		return &testBool{d.index}, nil
+	case kString:
+		return &stringVar{d.index, p.backward}, nil
+	}
+	p.calls = append(p.calls, callSite{d.routine, p.backward, line})
+	return &call{d.routine}, nil
+}
+
+// stringOperand parses the string of <-, insert and attach: a string, or a
+// string variable.  v is -1 for a string.
+func (p *parser) stringOperand() (s []rune, v int, err error) {
+	if p.tok.kind == tString {
+		s := p.tok.s
+		if s == nil {
+			s = []rune{}
+		}
+		return s, -1, p.next()
+	}
+	d, err := p.lookup(kString)
+	if err != nil {
+		return nil, 0, err
+	}
+	return nil, d.index, nil
+}
+
+// among parses among(...).
+func (p *parser) among() (command, error) {
+	a := p.pending
+	p.pending = nil
+	if a == nil {
+		a = &among{id: p.prog.amongs, backward: p.backward}
+		p.prog.amongs++
+	} else if a.backward != p.backward {
+		return nil, p.errorf("substring and among are in different modes")
+	}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var waiting []int // strings waiting for a command
+	for !p.is(")") {
+		switch {
+		case p.tok.kind == tString:
+			s := p.tok.s
+			if seen[string(s)] {
+				return nil, p.errorf("among has %q twice", string(s))
+			}
+			seen[string(s)] = true
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			as := amongString{s: s}
+			if p.tok.kind == tName {
+				line := p.tok.line
+				d, err := p.lookup(kRoutine)
+				if err != nil {
+					return nil, err
+				}
+				as.condition = d.routine
+				p.calls = append(p.calls, callSite{d.routine, p.backward, line})
+			}
+			waiting = append(waiting, len(a.strings))
+			a.strings = append(a.strings, as)
+		case p.is("("):
+			if len(waiting) == 0 {
+				return nil, p.errorf("among command has no strings")
+			}
+			c, err := p.primary()
+			if err != nil {
+				return nil, err
+			}
+			if s, ok := c.(sequence); !ok || len(s) > 0 {
+				a.commands = append(a.commands, c)
+				for _, i := range waiting {
+					a.strings[i].result = len(a.commands)
+				}
+			}
+			waiting = waiting[:0]
+		default:
+			return nil, p.errorf("expected a string or command in among")
+		}
+	}
+	sort.SliceStable(a.strings, func(i, j int) bool {
+		return len(a.strings[i].s) > len(a.strings[j].s)
+	})
+	return a, p.next()
+}
+
+// integerCommand parses $x = n, $x < n, $(n1 < n2) and so on.
+func (p *parser) integerCommand() (command, error) {
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	if p.is("(") {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		n1, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		op := p.tok.text
+		if !p.isRelation() {
+			return nil, p.errorf("expected a comparison")
+		}
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		n2, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		return &compare{n1, n2, op}, p.expect(")")
+	}
+	d, err := p.lookup(kInteger)
+	if err != nil {
+		return nil, err
+	}
+	op := p.tok.text
+	if p.tok.kind != tSymbol {
+		return nil, p.errorf("expected an assignment or comparison")
+	}
+	switch {
+	case p.isRelation():
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		n, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		v := d.index
+		return &compare{func(m *machine) int { return m.ints[v] }, n, op}, nil
+	case op == "=" || op == "+=" || op == "-=" || op == "*=" || op == "/=":
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		n, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		return &assign{d.index, op, n}, nil
+	}
+	return nil, p.errorf("expected an assignment or comparison")
+}
+
+// isRelation reports whether the current token compares integers.
+func (p *parser) isRelation() bool {
+	switch p.tok.text {
+	case "==", "!=", "<", "<=", ">", ">=":
+		return p.tok.kind == tSymbol
+	}
+	return false
+}
+
+// expr parses an arithmetic expression.
+func (p *parser) expr() (intExpr, error) {
+	n, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for p.is("+") || p.is("-") {
+		op := p.tok.text
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		n2, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		n1 := n
+		if op == "+" {
+			n = func(m *machine) int { return n1(m) + n2(m) }
+		} else {
+			n = func(m *machine) int { return n1(m) - n2(m) }
+		}
+	}
+	return n, nil
+}
+
+// term parses products and quotients.
+func (p *parser) term() (intExpr, error) {
+	n, err := p.factor()
+	if err != nil {
+		return nil, err
+	}
+	for p.is("*") || p.is("/") {
+		op := p.tok.text
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		n2, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		n1 := n
+		if op == "*" {
+			n = func(m *machine) int { return n1(m) * n2(m) }
+		} else {
+			n = func(m *machine) int {
+				if d := n2(m); d != 0 {
+					return n1(m) / d
+				}
+				return 0
+			}
+		}
+	}
+	return n, nil
+}
+
+// factor parses a number, variable, or expression in parentheses.
+func (p *parser) factor() (intExpr, error) {
+	backward := p.backward
+	switch {
+	case p.tok.kind == tNumber:
+		n, err := strconv.Atoi(p.tok.text)
+		if err != nil {
+			return nil, p.errorf("bad number %s", p.tok.text)
+		}
+		return func(*machine) int { return n }, p.next()
+	case p.is("-"):
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		n, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		return func(m *machine) int { return -n(m) }, nil
+	case p.is("("):
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		n, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		return n, p.expect(")")
+	case p.is("cursor"):
+		return func(m *machine) int { return m.c }, p.next()
+	case p.is("limit"):
+		return func(m *machine) int {
+			if backward {
+				return m.lb
+			}
+			return m.l
+		}, p.next()
+	case p.is("size"), p.is("len"):
+		return func(m *machine) int { return len(m.s) }, p.next()
+	case p.is("maxint"):
+		return func(*machine) int { return math.MaxInt32 }, p.next()
+	case p.is("minint"):
+		return func(*machine) int { return math.MinInt32 }, p.next()
+	}
+	d, err := p.lookup(kInteger)
+	if err != nil {
+		return nil, err
+	}
+	v := d.index
+	return func(m *machine) int { return m.ints[v] }, nil
+}
@@ -0,0 +1,52 @@
+package snowball
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src, exp string
+	}{
+		{"", "program has no external routine stem"},
+		{"routines ( r )\nexternals ( stem )\ndefine stem as r", "routine r is declared but not defined"},
+		{"externals ( stem )\ndefine stem as (\n  nothing\n)", "line 3: nothing is not declared"},
+		{"externals ( stem )\nexternals ( stem )", "line 2: stem is declared twice"},
+		{"externals ( stem )\ndefine stem as 'abc", "line 2: string is not closed"},
+		{"externals ( stem )\n/* comment\n", "line 2: comment is not closed"},
+		{"externals ( stem )\ndefine stem as substring", "line 2: substring in stem has no among"},
+		{"externals ( stem )\ndefine stem as among ( () )", "line 2: among command has no strings"},
+		{"externals ( stem )\ndefine stem as among ( 'a' 'a' )", "line 2: among has \"a\" twice"},
+		{"externals ( stem )\ndefine stem as reverse 'a'", "line 2: reverse is not supported"},
+		{"externals ( stem )\ndefine stem as 'a' % 'b'", "line 2: unexpected '%'"},
+		{"externals ( stem )\nbackwardmode ( define stem as 'a' )", "external routine stem is defined in backward mode"},
+		{"routines ( r )\nexternals ( stem )\nbackwardmode ( define r as 'a' )\ndefine stem as r", "line 4: routine r is called in the wrong mode"},
+		{"integers ( x )\nexternals ( stem )\ndefine stem as $x", "line 3: expected an assignment or comparison"},
+		{"booleans ( b )\nexternals ( stem )\ndefine stem as setmark b", "line 3: b is the wrong kind of name here"},
+		{"stringescapes {}\nexternals ( stem )\ndefine stem as '{x}'", "line 3: unknown escape \"x\""},
+	}
+	for _, test := range tests {
+		_, err := Read(strings.NewReader(test.src))
+		if err == nil || err.Error() != test.exp {
+			t.Errorf("Input: [%q] -> Actual: [%v]. Expected: [%s]", test.src, err, test.exp)
+		}
+	}
+}
+
+func TestStringEscapes(t *testing.T) {
+	p, err := Read(strings.NewReader(`
+stringescapes {}
+stringdef a^ hex 'E2'
+stringdef e' decimal '233'
+stringdef oe 'œ'
+externals ( stem )
+define stem as repeat ( gopast ( ['{a^}' or '{e'}' or '{oe}' or '{'}' or '{{}'] ) delete )
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stem := p.StemString("bâtéœ'{x"); stem != "btx" {
+		t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", "bâtéœ'{x", stem, "btx")
+	}
+}
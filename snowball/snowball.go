@@ -0,0 +1,217 @@
+// Package snowball runs stemmers written in Snowball, the string processing
+// language of https://snowballstem.org, so that an algorithm can be used from
+// its .sbl source rather than ported to Go by hand.
+//
+// A Program is read from Snowball source and interpreted.  It stems words in
+// place, like the stemmers of the porter package: StemWithoutLowerCasing
+// works on the runes it is given, and only copies them into new runes when a
+// rule makes the word longer than it was.  A Program implements porter.Stemmer:
+//
+//	p, err := snowball.Load("english.sbl")
+//	if err != nil {
+//		...
+//	}
+//	stem := p.StemString("generously") // "generous"
+//
+// The commands of the Snowball manual are supported, except string
+// variables as the target of commands ($s C), reverse, and get.  The
+// external routine that stems a word must be called stem.
+package snowball
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"unicode"
+)
+
+// Program is a Snowball stemmer.  A Program is safe for concurrent use.
+type Program struct {
+	stem *routine
+	// The number of each kind of variable, and of amongs, which a machine
+	// makes room for.
+	ints, bools, strs, amongs int
+}
+
+// Read reads a Snowball program.
+func Read(r io.Reader) (*Program, error) {
+	src, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return parse(string(src))
+}
+
+// Load reads a Snowball program from a file.
+func Load(filename string) (*Program, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	p, err := Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return p, nil
+}
+
+// StemString converts a string to a rune array, then stems the result.
+func (p *Program) StemString(s string) string {
+	ra := []rune(s)
+	ra = p.Stem(ra)
+	return string(ra)
+}
+
+// Stem converts the runes to lower case, then stems the lowercase runes.
+func (p *Program) Stem(s []rune) []rune {
+	for i := 0; i < len(s); i++ {
+		s[i] = unicode.ToLower(s[i])
+	}
+	return p.StemWithoutLowerCasing(s)
+}
+
+// StemWithoutLowerCasing runs the stem routine on runes which are already
+// lower case.
+func (p *Program) StemWithoutLowerCasing(s []rune) []rune {
+	if len(s) == 0 {
+		return s
+	}
+	m := machine{
+		s:     s[:len(s):len(s)],
+		l:     len(s),
+		ket:   len(s),
+		ints:  make([]int, p.ints),
+		bools: make([]bool, p.bools),
+		strs:  make([][]rune, p.strs),
+		found: make([]int, p.amongs),
+	}
+	p.stem.body.run(&m)
+	return m.s
+}
+
+// machine is the state of a program stemming a word: the word, the cursor c,
+// the limits l and lb, the slice from bra to ket, and the variables.
+type machine struct {
+	s        []rune
+	c, l, lb int
+	bra, ket int
+	ints     []int
+	bools    []bool
+	strs     [][]rune
+	found    []int // the result of each among's substring
+}
+
+// mark returns the cursor, as a position that survives changes to the word
+// that a command in the same direction makes.  Going backwards, the word
+// changes to the right of the cursor, so the mark is taken from the limit.
+func (m *machine) mark(backward bool) int {
+	if backward {
+		return m.l - m.c
+	}
+	return m.c
+}
+
+// reset moves the cursor back to a mark.
+func (m *machine) reset(backward bool, mark int) {
+	if backward {
+		m.c = m.l - mark
+	} else {
+		m.c = mark
+	}
+}
+
+// replace replaces the runes from bra to ket with r, and moves the cursor
+// and limit to match.  It returns how much longer the word got.  The word is
+// capped at its length before stemming, so if it grows past that, it grows
+// into new runes rather than over the runes after it.
+func (m *machine) replace(bra, ket int, r []rune) int {
+	adjustment := len(r) - (ket - bra)
+	n := len(m.s)
+	for i := 0; i < adjustment; i++ {
+		m.s = append(m.s, 0)
+	}
+	copy(m.s[ket+adjustment:], m.s[ket:n])
+	copy(m.s[bra:], r)
+	if adjustment < 0 {
+		m.s = m.s[:n+adjustment]
+	}
+	m.l += adjustment
+	if m.c >= ket {
+		m.c += adjustment
+	} else if m.c > bra {
+		m.c = bra
+	}
+	return adjustment
+}
+
+// sliceFrom replaces the slice with r.
+func (m *machine) sliceFrom(r []rune) bool {
+	if m.bra < 0 || m.bra > m.ket || m.ket > m.l {
+		return false
+	}
+	m.replace(m.bra, m.ket, r)
+	return true
+}
+
+// insert inserts r at the cursor, moving the slice if it is to the right.
+// The cursor ends up after r if after is true, and before it otherwise.
+func (m *machine) insert(r []rune, after bool) {
+	c := m.c
+	adjustment := m.replace(c, c, r)
+	if c <= m.bra {
+		m.bra += adjustment
+	}
+	if c <= m.ket {
+		m.ket += adjustment
+	}
+	if !after {
+		m.c = c
+	}
+}
+
+// eq reports whether r is at the cursor, and moves the cursor over it.
+func (m *machine) eq(r []rune, backward bool) bool {
+	if backward {
+		if m.c-m.lb < len(r) || !equal(m.s[m.c-len(r):m.c], r) {
+			return false
+		}
+		m.c -= len(r)
+		return true
+	}
+	if m.l-m.c < len(r) || !equal(m.s[m.c:m.c+len(r)], r) {
+		return false
+	}
+	m.c += len(r)
+	return true
+}
+
+// next moves the cursor over one letter.
+func (m *machine) next(backward bool) bool {
+	if backward {
+		if m.c <= m.lb {
+			return false
+		}
+		m.c--
+		return true
+	}
+	if m.c >= m.l {
+		return false
+	}
+	m.c++
+	return true
+}
+
+// equal reports whether a and b are the same runes.
+func equal(a, b []rune) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,156 @@
+package snowball
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	porter "github.com/ksshannon/go-porterstemmer"
+	"github.com/ksshannon/go-porterstemmer/stemmertest"
+)
+
+// readFields returns the whitespace separated fields of a file in the test
+// data folder of the porter package.
+func readFields(t testing.TB, name string) []string {
+	data, err := ioutil.ReadFile(filepath.Join("..", "testdata", name))
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	return strings.Fields(string(data))
+}
+
+// load loads a program from the test data folder.
+func load(t testing.TB, name string) *Program {
+	p, err := Load(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestVocabulary(t *testing.T) {
+	tests := []struct {
+		program, voc, output string
+		// own holds the stems where the program departs from the
+		// output on purpose.
+		own map[string]string
+	}{
+		{"porter.sbl", "voc.txt", "strict_output.txt", nil},
+		// output.txt is from the C reference, and porter_c.sbl never
+		// removes a step 1a suffix that is the whole word.
+		{"porter_c.sbl", "voc.txt", "output.txt", map[string]string{"ies": "ie"}},
+		{"english.sbl", "porter2_voc.txt", "porter2_output.txt", nil},
+		{"german.sbl", "german_voc.txt", "german_output.txt", nil},
+		{"german2.sbl", "german2_voc.txt", "german2_output.txt", nil},
+		{"spanish.sbl", "spanish_voc.txt", "spanish_output.txt", nil},
+	}
+	for _, test := range tests {
+		p := load(t, test.program)
+		vs := readFields(t, test.voc)
+		os := readFields(t, test.output)
+		if len(vs) != len(os) {
+			t.Fatalf("vocabulary has %d words but output has %d stems", len(vs), len(os))
+		}
+		for i, word := range vs {
+			exp := os[i]
+			if own, ok := test.own[word]; ok {
+				exp = own
+			}
+			if stem := p.StemString(word); stem != exp {
+				t.Errorf("%s: Input: [%s] -> Actual: [%s]. Expected: [%s]", test.program, word, stem, exp)
+			}
+		}
+	}
+}
+
+func TestPorterStemmers(t *testing.T) {
+	// The programs agree with the hand written stemmers on words that are
+	// not in the vocabulary, too.
+	spanish, err := porter.Lookup("spanish")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		program string
+		stemmer porter.Stemmer
+	}{
+		{"porter.sbl", porter.New(porter.Options{Strict: true})},
+		{"porter_c.sbl", porter.New(porter.Options{})},
+		{"german.sbl", porter.NewGerman(porter.GermanOptions{})},
+		{"german2.sbl", porter.NewGerman(porter.GermanOptions{Variant2: true})},
+		{"spanish.sbl", spanish},
+	}
+	for _, test := range tests {
+		p := load(t, test.program)
+		for _, word := range stemmertest.Words {
+			if stem, exp := p.StemString(word), test.stemmer.StemString(word); stem != exp {
+				t.Errorf("%s: Input: [%s] -> Actual: [%s]. Expected: [%s]", test.program, word, stem, exp)
+			}
+		}
+	}
+}
+
+func TestStemmer(t *testing.T) {
+	for _, name := range []string{"porter.sbl", "porter_c.sbl", "english.sbl", "german.sbl", "german2.sbl", "spanish.sbl"} {
+		if err := stemmertest.TestStemmer(load(t, name)); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestStemInPlace(t *testing.T) {
+	p := load(t, "porter.sbl")
+	s := []rune("generalizations")
+	stem := p.StemWithoutLowerCasing(s)
+	if string(stem) != "gener" {
+		t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", "generalizations", string(stem), "gener")
+	}
+	if &stem[0] != &s[0] {
+		t.Errorf("StemWithoutLowerCasing did not stem the runes in place")
+	}
+}
+
+func TestStemSubslice(t *testing.T) {
+	// The prelude of german.sbl makes the word longer, writing ß as ss.
+	p := load(t, "german.sbl")
+	buf := []rune("straße|tail")
+	stem := p.StemWithoutLowerCasing(buf[:6])
+	if string(stem) != "strass" {
+		t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", "straße", string(stem), "strass")
+	}
+	if tail := string(buf[6:]); tail != "|tail" {
+		t.Errorf("stemming [straße] overwrote the runes after it: [%s]", tail)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snowball")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "bad.sbl")
+	if err := ioutil.WriteFile(filename, []byte("externals ( stem )\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(filename); err == nil || !strings.HasPrefix(err.Error(), filename+": ") {
+		t.Errorf("Load(%q) = %v, expected an error that names the file", filename, err)
+	}
+	if _, err := Load(filepath.Join(dir, "missing.sbl")); err == nil {
+		t.Errorf("Load did not fail on a missing file")
+	}
+}
+
+func BenchmarkEnglish(b *testing.B) {
+	p := load(b, "english.sbl")
+	ss := readFields(b, "porter2_voc.txt")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, s := range ss {
+			stem := p.StemString(s)
+			_ = stem
+		}
+	}
+}
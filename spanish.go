@@ -0,0 +1,260 @@
+package porter
+
+import (
+	"unicode"
+)
+
+// This file implements the Spanish stemmer from the Snowball project.  For the
+// algorithm, see:
+//
+// http://snowball.tartarus.org/algorithms/spanish/stemmer.html
+//
+// Like the other stemmers, it works on the []rune it is given.  A suffix is
+// only ever replaced with a shorter one, so the stem is never longer than the
+// word.  The stem has no acute accents, but keeps ü and ñ.
+
+// The suffixes of each step.  Only the longest suffix that a word ends with
+// is ever considered.
+var (
+	spanishPronouns = []string{
+		"me", "se", "sela", "selo", "selas", "selos", "la", "le", "lo",
+		"las", "les", "los", "nos",
+	}
+	spanishPronounEndings = []string{
+		"iéndo", "ándo", "ár", "ér", "ír",
+		"ando", "iendo", "ar", "er", "ir",
+		"yendo",
+	}
+	spanishStandardSuffixes = []string{
+		"anza", "anzas", "ico", "ica", "icos", "icas", "ismo", "ismos",
+		"able", "ables", "ible", "ibles", "ista", "istas", "oso", "osa",
+		"osos", "osas", "amiento", "amientos", "imiento", "imientos",
+		"adora", "ador", "ación", "adoras", "adores", "aciones", "ante",
+		"antes", "ancia", "ancias",
+		"logía", "logías",
+		"ución", "uciones",
+		"encia", "encias",
+		"amente",
+		"mente",
+		"idad", "idades",
+		"iva", "ivo", "ivas", "ivos",
+	}
+	spanishYVerbSuffixes = []string{
+		"ya", "ye", "yan", "yen", "yeron", "yendo", "yo", "yó", "yas", "yes",
+		"yais", "yamos",
+	}
+	spanishVerbSuffixes = []string{
+		"en", "es", "éis", "emos",
+		"arían", "arías", "arán", "arás", "aríais", "aría", "aréis",
+		"aríamos", "aremos", "ará", "aré", "erían", "erías", "erán", "erás",
+		"eríais", "ería", "eréis", "eríamos", "eremos", "erá", "eré", "irían",
+		"irías", "irán", "irás", "iríais", "iría", "iréis", "iríamos",
+		"iremos", "irá", "iré", "aba", "ada", "ida", "ía", "ara", "iera",
+		"ad", "ed", "id", "ase", "iese", "aste", "iste", "an", "aban", "ían",
+		"aran", "ieran", "asen", "iesen", "aron", "ieron", "ado", "ido",
+		"ando", "iendo", "ió", "ar", "er", "ir", "as", "abas", "adas", "idas",
+		"ías", "aras", "ieras", "ases", "ieses", "ís", "áis", "abais", "íais",
+		"arais", "ierais", "aseis", "ieseis", "asteis", "isteis", "ados",
+		"idos", "amos", "ábamos", "íamos", "imos", "áramos", "iéramos",
+		"iésemos", "ásemos",
+	}
+	spanishResidualSuffixes = []string{"os", "a", "o", "á", "í", "ó", "e", "é"}
+)
+
+// isSpanishVowel returns true if the rune is a vowel.
+func isSpanishVowel(r rune) bool {
+	switch r {
+	case 'a', 'e', 'i', 'o', 'u', 'á', 'é', 'í', 'ó', 'ú', 'ü':
+		return true
+	}
+	return false
+}
+
+// spanishUnAccent replaces an acute accented vowel with the plain vowel.
+func spanishUnAccent(r rune) rune {
+	switch r {
+	case 'á':
+		return 'a'
+	case 'é':
+		return 'e'
+	case 'í':
+		return 'i'
+	case 'ó':
+		return 'o'
+	case 'ú':
+		return 'u'
+	}
+	return r
+}
+
+// spanishAttachedPronoun removes a pronoun attached to a gerund or an
+// infinitive in RV, and the accent that the verb then no longer needs.
+func spanishAttachedPronoun(s []rune, rv int) []rune {
+	pronoun, i := longestSuffix(s, 0, spanishPronouns)
+	if pronoun == "" {
+		return s
+	}
+	ending, j := longestSuffix(s[:i], 0, spanishPronounEndings)
+	if ending == "" || j < rv {
+		return s
+	}
+	switch ending {
+	case "iéndo", "ándo", "ár", "ér", "ír":
+		s = s[:i]
+		for k := j; k < i; k++ {
+			s[k] = spanishUnAccent(s[k])
+		}
+	case "yendo":
+		if j > 0 && s[j-1] == 'u' {
+			s = s[:i]
+		}
+	default:
+		s = s[:i]
+	}
+	return s
+}
+
+// spanishStandardSuffix removes or replaces the longest standard suffix.  It
+// returns false if no suffix was removed.
+func spanishStandardSuffix(s []rune, r1, r2 int) ([]rune, bool) {
+	suffix, i := longestSuffix(s, 0, spanishStandardSuffixes)
+	switch suffix {
+	case "":
+		return s, false
+	case "amente":
+		if i < r1 {
+			return s, false
+		}
+		s = s[:i]
+		suffix, i = longestSuffix(s, 0, []string{"iv", "os", "ic", "ad"})
+		if suffix != "" && i >= r2 {
+			s = s[:i]
+			if suffix == "iv" && endsWithString(s, "at") && i-2 >= r2 {
+				s = s[:i-2]
+			}
+		}
+		return s, true
+	}
+	if i < r2 {
+		return s, false
+	}
+	switch suffix {
+	case "adora", "ador", "ación", "adoras", "adores", "aciones", "ante",
+		"antes", "ancia", "ancias":
+		s = s[:i]
+		if endsWithString(s, "ic") && i-2 >= r2 {
+			s = s[:i-2]
+		}
+	case "logía", "logías":
+		s = replaceSuffix(s, len(s)-i, "log")
+	case "ución", "uciones":
+		s = replaceSuffix(s, len(s)-i, "u")
+	case "encia", "encias":
+		s = replaceSuffix(s, len(s)-i, "ente")
+	case "mente":
+		s = s[:i]
+		if suffix, i = longestSuffix(s, 0, []string{"ante", "able", "ible"}); suffix != "" && i >= r2 {
+			s = s[:i]
+		}
+	case "idad", "idades":
+		s = s[:i]
+		if suffix, i = longestSuffix(s, 0, []string{"abil", "ic", "iv"}); suffix != "" && i >= r2 {
+			s = s[:i]
+		}
+	case "iva", "ivo", "ivas", "ivos":
+		s = s[:i]
+		if endsWithString(s, "at") && i-2 >= r2 {
+			s = s[:i-2]
+		}
+	default:
+		s = s[:i]
+	}
+	return s, true
+}
+
+// spanishYVerbSuffix removes the longest suffix in RV that begins with y, if
+// it follows a u.
+func spanishYVerbSuffix(s []rune, rv int) ([]rune, bool) {
+	suffix, i := longestSuffix(s, rv, spanishYVerbSuffixes)
+	if suffix == "" || i == 0 || s[i-1] != 'u' {
+		return s, false
+	}
+	return s[:i], true
+}
+
+// spanishVerbSuffix removes the longest other verb suffix in RV.  The u of a
+// gu before en, es, éis or emos is removed as well.
+func spanishVerbSuffix(s []rune, rv int) ([]rune, bool) {
+	suffix, i := longestSuffix(s, rv, spanishVerbSuffixes)
+	switch suffix {
+	case "":
+		return s, false
+	case "en", "es", "éis", "emos":
+		if i >= 2 && s[i-1] == 'u' && s[i-2] == 'g' {
+			i--
+		}
+	}
+	return s[:i], true
+}
+
+// spanishResidualSuffix removes a final os, a, o, á, í, ó, e or é in RV.  The
+// u of a gu before an e or é is removed as well, if it is in RV.
+func spanishResidualSuffix(s []rune, rv int) []rune {
+	suffix, i := longestSuffix(s, 0, spanishResidualSuffixes)
+	if suffix == "" || i < rv {
+		return s
+	}
+	s = s[:i]
+	if (suffix == "e" || suffix == "é") && i-1 >= rv && s[i-1] == 'u' && i >= 2 && s[i-2] == 'g' {
+		s = s[:i-1]
+	}
+	return s
+}
+
+// SpanishStemString converts a string to a rune array, then stems the result
+// with the Spanish algorithm.
+func SpanishStemString(s string) string {
+	ra := []rune(s)
+	ra = SpanishStem(ra)
+	return string(ra)
+}
+
+// SpanishStem converts the runes to lower case, then stems the lowercase runes
+// with the Spanish algorithm.
+func SpanishStem(s []rune) []rune {
+	if len(s) == 0 {
+		return s
+	}
+	for i := 0; i < len(s); i++ {
+		s[i] = unicode.ToLower(s[i])
+	}
+	return SpanishStemWithoutLowerCasing(s)
+}
+
+// SpanishStemWithoutLowerCasing applies the Spanish stemming assuming that the
+// runes are lowercase.
+func SpanishStemWithoutLowerCasing(s []rune) []rune {
+	rv, r1, r2 := romanceRegions(s, isSpanishVowel)
+
+	s = spanishAttachedPronoun(s, rv)
+	s, ok := spanishStandardSuffix(s, r1, r2)
+	if !ok {
+		s, ok = spanishYVerbSuffix(s, rv)
+	}
+	if !ok {
+		s, _ = spanishVerbSuffix(s, rv)
+	}
+	s = spanishResidualSuffix(s, rv)
+
+	for i := 0; i < len(s); i++ {
+		s[i] = spanishUnAccent(s[i])
+	}
+	return s
+}
+
+// spanish is the Spanish algorithm as a Stemmer.
+type spanish struct{}
+
+func (spanish) StemString(s string) string             { return SpanishStemString(s) }
+func (spanish) Stem(s []rune) []rune                   { return SpanishStem(s) }
+func (spanish) StemWithoutLowerCasing(s []rune) []rune { return SpanishStemWithoutLowerCasing(s) }
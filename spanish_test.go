@@ -0,0 +1,87 @@
+package porter
+
+import (
+	"testing"
+)
+
+func TestSpanishAttachedPronoun(t *testing.T) {
+	tests := []struct {
+		s, exp string
+	}{
+		{"diciéndole", "diciendo"},
+		{"ayudándoles", "ayudando"},
+		{"comprarlo", "comprar"},
+		{"construyéndolo", "construyéndolo"},
+		{"arguyendolo", "arguyendo"},
+		{"hablarme", "hablar"},
+		{"los", "los"},
+	}
+	for _, test := range tests {
+		s := []rune(test.s)
+		rv, _, _ := romanceRegions(s, isSpanishVowel)
+		if stem := string(spanishAttachedPronoun(s, rv)); stem != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.s, stem, test.exp)
+		}
+	}
+}
+
+func TestSpanishStemString(t *testing.T) {
+	tests := []struct {
+		s, exp string
+	}{
+		{"", ""},
+		{"a", "a"},
+		{"Cantando", "cant"},
+		{"cantaríamos", "cant"},
+		{"diciéndole", "dic"},
+		{"haciéndolo", "hac"},
+		{"construyendo", "constru"},
+		{"huyeron", "huyeron"},
+		{"arguyo", "argu"},
+		{"distribuyen", "distribu"},
+		{"averigüemos", "averigü"},
+		{"averiguéis", "averig"},
+		{"naciones", "nacion"},
+		{"nacionalidad", "nacional"},
+		{"rápidamente", "rapid"},
+		{"felizmente", "feliz"},
+		{"lógicas", "logic"},
+		{"evolución", "evolu"},
+		{"tendencia", "tendenci"},
+		{"relativas", "relat"},
+		{"llegue", "lleg"},
+		{"jóvenes", "joven"},
+		{"canción", "cancion"},
+		{"niños", "niñ"},
+	}
+	for _, test := range tests {
+		if stem := SpanishStemString(test.s); stem != test.exp {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", test.s, stem, test.exp)
+		}
+	}
+}
+
+func TestSpanishVocabulary(t *testing.T) {
+	vs := readFields(t, "spanish_voc.txt")
+	os := readFields(t, "spanish_output.txt")
+	if len(vs) != len(os) {
+		t.Fatalf("vocabulary has %d words but output has %d stems", len(vs), len(os))
+	}
+	for i, word := range vs {
+		stem := SpanishStemString(word)
+		if stem != os[i] {
+			t.Errorf("Input: [%s] -> Actual: [%s]. Expected: [%s]", word, stem, os[i])
+		}
+	}
+}
+
+func BenchmarkSpanishString(b *testing.B) {
+	ss := readFields(b, "spanish_voc.txt")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, s := range ss {
+			stem := SpanishStemString(s)
+			_ = stem
+		}
+	}
+}
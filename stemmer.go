@@ -0,0 +1,86 @@
+package porter
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Stemmer is a stemming algorithm.  Every Stemmer must be safe for concurrent
+// use.
+type Stemmer interface {
+	// StemString returns the stem of a word.
+	StemString(s string) string
+	// Stem converts the runes to lower case, then stems them.  It may reuse
+	// the runes it is given for the stem.
+	Stem(s []rune) []rune
+	// StemWithoutLowerCasing stems runes which are already lower case.  It
+	// may reuse the runes it is given for the stem.
+	StemWithoutLowerCasing(s []rune) []rune
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Stemmer)
+)
+
+func init() {
+	Register("porter", defaultPorter)
+	Register("porter-strict", New(Options{Strict: true}))
+	Register("porter-light", New(Options{Level: Light}))
+	Register("porter-medium", New(Options{Level: Medium}))
+	Register("porter-fixpoint", New(Options{MaxIterations: FixpointIterations}))
+	Register("porter2", porter2{})
+	Register("lancaster", defaultLancaster)
+	Register("harman", harman{})
+	Register("kstem", defaultKStem)
+	Register("lovins", lovins{})
+	Register("french", french{})
+	Register("german", defaultGerman)
+	Register("german2", NewGerman(GermanOptions{Variant2: true}))
+	Register("spanish", spanish{})
+	Register("portuguese", portuguese{})
+	Register("russian", defaultRussian)
+}
+
+// Register makes a stemmer available by name to Lookup.  It is meant to be
+// called from the init function of the package that implements the stemmer.
+// It panics if the name is empty, if s is nil, or if the name is already
+// registered.
+func Register(name string, s Stemmer) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if name == "" {
+		panic("porter: Register with an empty name")
+	}
+	if s == nil {
+		panic("porter: Register stemmer is nil")
+	}
+	if _, dup := registry[name]; dup {
+		panic("porter: Register called twice for stemmer " + name)
+	}
+	registry[name] = s
+}
+
+// Lookup returns the stemmer registered with a name.
+func Lookup(name string) (Stemmer, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	s, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("porter: unknown stemmer %q", name)
+	}
+	return s, nil
+}
+
+// Names returns the names of the registered stemmers, in sorted order.
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
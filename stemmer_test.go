@@ -0,0 +1,75 @@
+package porter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNames(t *testing.T) {
+	names := Names()
+	for _, name := range []string{"porter", "porter-strict", "porter2"} {
+		found := false
+		for _, n := range names {
+			found = found || n == name
+		}
+		if !found {
+			t.Errorf("%s is not registered: %v", name, names)
+		}
+	}
+	for i := 1; i < len(names); i++ {
+		if names[i-1] >= names[i] {
+			t.Errorf("Names are not sorted: %v", names)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name, word, stem string
+	}{
+		{"porter", "analogy", "analog"},
+		{"porter-strict", "analogy", "analogi"},
+		{"porter2", "generously", "generous"},
+	}
+	for _, test := range tests {
+		s, err := Lookup(test.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stem := s.StemString(test.word); stem != test.stem {
+			t.Errorf("%s: Input: [%s] -> Actual: [%s]. Expected: [%s]", test.name, test.word, stem, test.stem)
+		}
+	}
+	if _, err := Lookup("nonesuch"); err == nil {
+		t.Error("Expected an error for an unknown stemmer")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	expectPanic := func(name string, s Stemmer) {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Expected Register(%q, %v) to panic", name, s)
+			}
+		}()
+		Register(name, s)
+	}
+	expectPanic("", defaultPorter)
+	expectPanic("test", nil)
+	expectPanic("porter", defaultPorter)
+
+	s := New(Options{Protected: map[string]bool{"cats": true}})
+	Register("test-protected", s)
+	defer func() {
+		registryMu.Lock()
+		delete(registry, "test-protected")
+		registryMu.Unlock()
+	}()
+	got, err := Lookup("test-protected")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, Stemmer(s)) {
+		t.Errorf("Lookup returned %v, not the registered stemmer", got)
+	}
+}
@@ -0,0 +1,126 @@
+// Package stemmertest implements support for testing implementations of
+// porter.Stemmer.
+//
+// A package which registers its own stemmer can check it with:
+//
+//	func TestStemmer(t *testing.T) {
+//		if err := stemmertest.TestStemmer(mystemmer.New()); err != nil {
+//			t.Fatal(err)
+//		}
+//	}
+package stemmertest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+
+	porter "github.com/ksshannon/go-porterstemmer"
+)
+
+// Words are the words TestStemmer checks, besides any it is given.  They
+// mix upper and lower case, letters outside of ASCII, digits, punctuation,
+// and words of every length from empty to very long.
+var Words = []string{
+	"", "a", "I", "s", "is", "as", "by", "sky", "skies",
+	"caresses", "ponies", "ties", "cats", "feed", "agreed", "plastered",
+	"motoring", "sing", "conflated", "troubled", "sized", "hopping",
+	"tanned", "falling", "hissing", "fizzed", "failing", "filing",
+	"happy", "relational", "conditional", "rational", "valenci",
+	"digitizer", "conformabli", "radicalli", "differentli", "vileli",
+	"analogousli", "vietnamization", "predication", "operator",
+	"feudalism", "decisiveness", "hopefulness", "callousness", "formaliti",
+	"sensitiviti", "sensibiliti", "triplicate", "formative", "formalize",
+	"electriciti", "electrical", "hopeful", "goodness", "revival",
+	"allowance", "inference", "airliner", "gyroscopic", "adjustable",
+	"defensible", "irritant", "replacement", "adjustment", "dependent",
+	"adoption", "homologou", "communism", "activate", "angulariti",
+	"homologous", "effective", "bowdlerize", "probate", "rate", "cease",
+	"controll", "roll", "generalizations", "oscillators", "knightly",
+	"Running", "CATS", "Generalization", "HOPEFULNESS", "McDonald's",
+	"don't", "o'clock", "well-known", "e-mail", "x1", "mp3", "1990s",
+	"12345", "-", "'", "''", "'s", "...", "a.b.c", "naïve", "café",
+	"über", "Straße", "Ärger", "façade", "résumé", "ÀÉÎÕÜ", "niño",
+	"corações", "бегать", "Прекрасная", "ёлка", "ἄνθρωπος", "日本語",
+	"́", "�", "\x80", "a\x00b",
+	strings.Repeat("a", 100), strings.Repeat("ab", 100),
+	strings.Repeat("ization", 20), strings.Repeat("y", 50),
+}
+
+// lower lower cases a word a rune at a time, as Stem does.
+func lower(word string) string {
+	s := []rune(word)
+	for i := range s {
+		s[i] = unicode.ToLower(s[i])
+	}
+	return string(s)
+}
+
+// TestStemmer checks that a stemmer behaves as a porter.Stemmer should, for
+// Words and the words given:
+//
+//   - StemString, Stem and StemWithoutLowerCasing agree with each other.
+//   - Stem gives the same stem for a word in any case.
+//   - Stemming a word again gives the same stem.
+//   - The empty word has an empty stem.
+//   - Stemming from many goroutines at once gives the same stems.
+//
+// It returns an error describing every problem it finds, or nil.
+func TestStemmer(s porter.Stemmer, words ...string) error {
+	var errs []string
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Sprintf(format, args...))
+	}
+
+	all := append(append([]string{}, Words...), words...)
+	want := make(map[string]string, len(all))
+	for _, word := range all {
+		stem := s.StemString(word)
+		want[word] = stem
+		if again := s.StemString(word); again != stem {
+			fail("StemString(%q) = %q, then %q", word, stem, again)
+		}
+		if got := string(s.Stem([]rune(word))); got != stem {
+			fail("Stem(%q) = %q, StemString = %q", word, got, stem)
+		}
+		l := lower(word)
+		if got := s.StemString(l); got != stem {
+			fail("StemString(%q) = %q, StemString(%q) = %q", word, stem, l, got)
+		}
+		if got := string(s.StemWithoutLowerCasing([]rune(l))); got != stem {
+			fail("StemWithoutLowerCasing(%q) = %q, StemString = %q", l, got, stem)
+		}
+	}
+	if got := s.Stem(nil); len(got) != 0 {
+		fail("Stem(nil) = %q, want empty", string(got))
+	}
+	if got := s.StemWithoutLowerCasing([]rune{}); len(got) != 0 {
+		fail("StemWithoutLowerCasing([]rune{}) = %q, want empty", string(got))
+	}
+
+	const goroutines = 8
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := range all {
+				word := all[(i+g)%len(all)]
+				if stem := s.StemString(word); stem != want[word] {
+					mu.Lock()
+					fail("concurrent StemString(%q) = %q, want %q", word, stem, want[word])
+					mu.Unlock()
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return errors.New("stemmertest: " + strings.Join(errs, "\n\t"))
+	}
+	return nil
+}
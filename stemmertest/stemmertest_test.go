@@ -0,0 +1,39 @@
+package stemmertest
+
+import (
+	"strings"
+	"testing"
+
+	porter "github.com/ksshannon/go-porterstemmer"
+)
+
+// TestRegistered runs the conformance tests against every registered
+// stemmer.
+func TestRegistered(t *testing.T) {
+	for _, name := range porter.Names() {
+		s, err := porter.Lookup(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := TestStemmer(s); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+// caseSensitive is a stemmer that forgets to lower case in Stem.
+type caseSensitive struct{}
+
+func (caseSensitive) StemString(s string) string             { return s }
+func (caseSensitive) Stem(s []rune) []rune                   { return s }
+func (caseSensitive) StemWithoutLowerCasing(s []rune) []rune { return s }
+
+func TestBrokenStemmer(t *testing.T) {
+	err := TestStemmer(caseSensitive{})
+	if err == nil {
+		t.Fatal("Expected an error for a stemmer that does not lower case")
+	}
+	if !strings.Contains(err.Error(), `StemString("CATS") = "CATS", StemString("cats") = "cats"`) {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package porter
+
+// suffixIndex is a Step with its suffixes in a trie, read from the last
+// letter back, so that the rule that matches a word is found in one pass
+// over the end of the word, rather than by trying each rule in turn.
+type suffixIndex struct {
+	step  Step
+	nodes []suffixNode
+}
+
+// suffixNode is a node of the trie.  The root is nodes[0].
+type suffixNode struct {
+	// rule is the index of the first rule whose suffix ends at this node, or
+	// -1 if there is none.
+	rule  int
+	edges []suffixEdge
+}
+
+// suffixEdge leads to the node for one more letter of a suffix.
+type suffixEdge struct {
+	r    rune
+	next int
+}
+
+// newSuffixIndex builds the trie for the rules of a step.
+func newSuffixIndex(st Step) *suffixIndex {
+	x := &suffixIndex{step: st, nodes: []suffixNode{{rule: -1}}}
+	for i, rule := range st {
+		suffix := []rune(rule.Suffix)
+		n := 0
+		for j := len(suffix) - 1; j >= 0; j-- {
+			next := x.next(n, suffix[j])
+			if next < 0 {
+				next = len(x.nodes)
+				x.nodes = append(x.nodes, suffixNode{rule: -1})
+				x.nodes[n].edges = append(x.nodes[n].edges, suffixEdge{suffix[j], next})
+			}
+			n = next
+		}
+		if x.nodes[n].rule < 0 {
+			x.nodes[n].rule = i
+		}
+	}
+	return x
+}
+
+// next returns the node reached from node n by the rune r, or -1.
+func (x *suffixIndex) next(n int, r rune) int {
+	for _, e := range x.nodes[n].edges {
+		if e.r == r {
+			return e.next
+		}
+	}
+	return -1
+}
+
+// match returns the first rule of the step whose suffix matches s, leaving at
+// least one rune before it, or nil.  It is the same rule as Step.match
+// returns.  n is the length of the suffix, in runes.
+func (x *suffixIndex) match(s []rune) (rule *Rule, n int) {
+	best, bestLen := x.nodes[0].rule, 0
+	node := 0
+	for i := len(s) - 1; i >= 1; i-- {
+		if node = x.next(node, s[i]); node < 0 {
+			break
+		}
+		if r := x.nodes[node].rule; r >= 0 && (best < 0 || r < best) {
+			best, bestLen = r, len(s)-i
+		}
+	}
+	if best < 0 {
+		return nil, 0
+	}
+	return &x.step[best], bestLen
+}
+
+// apply applies the step to a word, whose conditions must all be built in.
+func (x *suffixIndex) apply(w *word) {
+	if r, n := x.match(w.s); r != nil && r.Condition.eval(w, len(w.s)-n) {
+		w.replaceSuffix(n, r.Replacement)
+	}
+}
+
+// The indexes of steps 2 to 4 of the Porter algorithm.
+var (
+	porterStep2Index       = newSuffixIndex(porterStep2)
+	porterStep2StrictIndex = newSuffixIndex(porterStep2Strict)
+	porterStep3Index       = newSuffixIndex(porterStep3)
+	porterStep4Index       = newSuffixIndex(porterStep4)
+)
@@ -0,0 +1,56 @@
+package porter
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// randomWords returns words made of the letters that end the suffixes of
+// steps 2 to 4, so that most of them match some rule.
+func randomWords(n int, seed int64) []string {
+	const letters = "abcegilnorstuvyz"
+	rnd := rand.New(rand.NewSource(seed))
+	words := make([]string, n)
+	for i := range words {
+		w := make([]byte, 1+rnd.Intn(12))
+		for j := range w {
+			w[j] = letters[rnd.Intn(len(letters))]
+		}
+		words[i] = string(w)
+	}
+	return words
+}
+
+func TestSuffixIndex(t *testing.T) {
+	steps := []Step{porterStep2, porterStep2Strict, porterStep3, porterStep4, {
+		{Suffix: "ß", Replacement: "ss"},
+		{Suffix: "eß", Replacement: "e"},
+		{Suffix: "s", Replacement: ""},
+		{Suffix: "ss", Replacement: "ss"},
+		{Suffix: "s", Replacement: "x"},
+		{Suffix: "", Replacement: "e"},
+	}}
+	words := append(getVoc(), randomWords(100000, 1)...)
+	words = append(words, "", "s", "ß", "eß", "straß", "ss", "ational")
+	for _, step := range steps {
+		x := newSuffixIndex(step)
+		for _, word := range words {
+			s := []rune(word)
+			if got, n := x.match(s); got != step.match(s) || got != nil && n != len([]rune(got.Suffix)) {
+				t.Errorf("match(%q) = %v, %d, expected %v", word, got, n, step.match(s))
+			}
+		}
+	}
+}
+
+func TestSuffixIndexAllocs(t *testing.T) {
+	s := []rune("generalization")
+	for name, step := range map[string]func([]rune) []rune{"step2": Step2, "step3": Step3, "step4": Step4} {
+		allocs := testing.AllocsPerRun(100, func() {
+			step(s)
+		})
+		if allocs != 0 {
+			t.Errorf("%s made %v allocations, expected none", name, allocs)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package porter
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Token is a word found by a Tokenizer, and its stem.
+type Token struct {
+	// Term is the word as it appears in the text.
+	Term string
+	// Stem is the stem of the word.
+	Stem string
+	// Start and End are the byte offsets of the word in the text.
+	Start, End int
+	// Position is the number of the word in the text, counting from 0, and
+	// including words which were skipped for being too short or too long.
+	Position int
+	// PositionIncrement is how many positions the word is after the previous
+	// one.  It is 1, unless words were skipped in between.
+	PositionIncrement int
+}
+
+// TokenizerOptions configures a Tokenizer.  By default, a word is a run of
+// ASCII letters.
+type TokenizerOptions struct {
+	// Apostrophes keeps an apostrophe between two letters as part of a word
+	// (e.g., "don't"), rather than splitting the word there.
+	Apostrophes bool
+	// Hyphens keeps a hyphen between two letters as part of a word (e.g.,
+	// "well-known"), rather than splitting the word there.
+	Hyphens bool
+	// Digits makes digits part of words, rather than separators.
+	Digits bool
+	// Unicode makes letters outside of ASCII part of words, rather than
+	// separators.
+	Unicode bool
+	// MinLength and MaxLength, if not zero, skip words with fewer or more
+	// runes.
+	MinLength, MaxLength int
+	// Stemmer stems the words.  If nil, the words are stemmed with the C
+	// reference rules, like StemString.
+	Stemmer Stemmer
+}
+
+// Tokenizer splits text into words and stems each one.  It reads the text
+// as it goes, so it never holds more than one word in memory.
+//
+// Use it like a bufio.Scanner:
+//
+//	t := NewTokenizer(r, TokenizerOptions{})
+//	for t.Scan() {
+//		tok := t.Token()
+//		...
+//	}
+//	if err := t.Err(); err != nil {
+//		...
+//	}
+type Tokenizer struct {
+	r       *bufio.Reader
+	opts    TokenizerOptions
+	offset  int
+	pos     int
+	skipped int
+	term    []byte
+	tok     Token
+	err     error
+}
+
+// NewTokenizer returns a Tokenizer that reads text from r.
+func NewTokenizer(r io.Reader, opts TokenizerOptions) *Tokenizer {
+	if opts.Stemmer == nil {
+		opts.Stemmer = defaultPorter
+	}
+	return &Tokenizer{
+		r:    bufio.NewReader(r),
+		opts: opts,
+		pos:  -1,
+	}
+}
+
+// Tokenize splits a string into words, and stems each one.
+func Tokenize(s string, opts TokenizerOptions) []Token {
+	var toks []Token
+	t := NewTokenizer(strings.NewReader(s), opts)
+	for t.Scan() {
+		toks = append(toks, t.Token())
+	}
+	return toks
+}
+
+// isWordRune returns true if the rune is part of a word.
+func (t *Tokenizer) isWordRune(r rune) bool {
+	if r < utf8.RuneSelf {
+		return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || t.opts.Digits && '0' <= r && r <= '9'
+	}
+	if !t.opts.Unicode {
+		return false
+	}
+	return unicode.IsLetter(r) || unicode.IsMark(r) || t.opts.Digits && unicode.IsDigit(r)
+}
+
+// isJoiner returns true if the rune is kept as part of a word when it is
+// between two word runes.
+func (t *Tokenizer) isJoiner(r rune) bool {
+	switch r {
+	case '\'', '’':
+		return t.opts.Apostrophes
+	case '-', '‐':
+		return t.opts.Hyphens
+	}
+	return false
+}
+
+// readRune reads the next rune, keeping track of the byte offset.
+func (t *Tokenizer) readRune() (rune, int, error) {
+	r, size, err := t.r.ReadRune()
+	t.offset += size
+	return r, size, err
+}
+
+// unreadRune unreads the rune read last, which was size bytes.
+func (t *Tokenizer) unreadRune(size int) {
+	t.r.UnreadRune()
+	t.offset -= size
+}
+
+// next reads the next word.  It returns an empty word at the end of the text.
+func (t *Tokenizer) next() (start, runes int, err error) {
+	t.term = t.term[:0]
+	var r rune
+	var size int
+	for {
+		if r, size, err = t.readRune(); err != nil {
+			return t.offset, 0, err
+		}
+		if t.isWordRune(r) {
+			break
+		}
+	}
+	start = t.offset - size
+	for {
+		t.term = append(t.term, string(r)...)
+		runes++
+		if r, _, err = t.readRune(); err != nil {
+			return start, runes, err
+		}
+		if t.isWordRune(r) {
+			continue
+		}
+		if !t.isJoiner(r) {
+			return start, runes, nil
+		}
+		joiner := r
+		if r, size, err = t.readRune(); err != nil {
+			// The joiner ends the text, so it is not part of the word.
+			return start, runes, err
+		}
+		if !t.isWordRune(r) {
+			t.unreadRune(size)
+			return start, runes, nil
+		}
+		t.term = append(t.term, string(joiner)...)
+		runes++
+	}
+}
+
+// Scan advances to the next word, which is then available from Token.  It
+// returns false at the end of the text, or on an error.
+func (t *Tokenizer) Scan() bool {
+	for t.err == nil {
+		start, runes, err := t.next()
+		if err != nil {
+			t.err = err
+		}
+		if runes == 0 {
+			break
+		}
+		end := start + len(t.term)
+		if t.opts.MinLength > 0 && runes < t.opts.MinLength || t.opts.MaxLength > 0 && runes > t.opts.MaxLength {
+			t.skipped++
+			continue
+		}
+		term := string(t.term)
+		t.tok = Token{
+			Term:              term,
+			Stem:              t.opts.Stemmer.StemString(term),
+			Start:             start,
+			End:               end,
+			Position:          t.pos + 1 + t.skipped,
+			PositionIncrement: 1 + t.skipped,
+		}
+		t.pos = t.tok.Position
+		t.skipped = 0
+		return true
+	}
+	return false
+}
+
+// Token returns the word found by the last call to Scan.
+func (t *Tokenizer) Token() Token {
+	return t.tok
+}
+
+// Err returns the first error reading the text, other than io.EOF.
+func (t *Tokenizer) Err() error {
+	if t.err == io.EOF {
+		return nil
+	}
+	return t.err
+}
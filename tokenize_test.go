@@ -0,0 +1,112 @@
+package porter
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestTokenize(t *testing.T) {
+	text := "The runner's well-known shoes, bought in 2019 for naïve fans -- running again!"
+	tests := []struct {
+		opts  TokenizerOptions
+		terms []string
+		stems []string
+	}{
+		{
+			TokenizerOptions{},
+			[]string{"The", "runner", "s", "well", "known", "shoes", "bought", "in", "for", "na", "ve", "fans", "running", "again"},
+			[]string{"the", "runner", "s", "well", "known", "shoe", "bought", "in", "for", "na", "ve", "fan", "run", "again"},
+		},
+		{
+			TokenizerOptions{Apostrophes: true, Hyphens: true, Digits: true, Unicode: true},
+			[]string{"The", "runner's", "well-known", "shoes", "bought", "in", "2019", "for", "naïve", "fans", "running", "again"},
+			[]string{"the", "runner'", "well-known", "shoe", "bought", "in", "2019", "for", "naïv", "fan", "run", "again"},
+		},
+	}
+	for _, test := range tests {
+		toks := Tokenize(text, test.opts)
+		if len(toks) != len(test.terms) {
+			t.Errorf("Tokenize(%+v) returned %d tokens, expected %d: %+v", test.opts, len(toks), len(test.terms), toks)
+			continue
+		}
+		for i, tok := range toks {
+			if tok.Term != test.terms[i] || tok.Stem != test.stems[i] {
+				t.Errorf("token %d = %q with stem %q, expected %q with stem %q", i, tok.Term, tok.Stem, test.terms[i], test.stems[i])
+			}
+			if text[tok.Start:tok.End] != tok.Term {
+				t.Errorf("token %q has offsets [%d:%d], which is %q", tok.Term, tok.Start, tok.End, text[tok.Start:tok.End])
+			}
+			if tok.Position != i || tok.PositionIncrement != 1 {
+				t.Errorf("token %q has position %d and increment %d, expected %d and 1", tok.Term, tok.Position, tok.PositionIncrement, i)
+			}
+		}
+	}
+}
+
+func TestTokenizeJoinersAtEdges(t *testing.T) {
+	opts := TokenizerOptions{Apostrophes: true, Hyphens: true}
+	toks := Tokenize("'quoted' rock--roll trailing-", opts)
+	var terms []string
+	for _, tok := range toks {
+		terms = append(terms, tok.Term)
+	}
+	if got, exp := strings.Join(terms, " "), "quoted rock roll trailing"; got != exp {
+		t.Errorf("Tokenize returned %q, expected %q", got, exp)
+	}
+}
+
+func TestTokenizePositionIncrements(t *testing.T) {
+	toks := Tokenize("a big cat is on the mat", TokenizerOptions{MinLength: 3})
+	exp := []struct {
+		term          string
+		pos, posIncrC int
+	}{
+		{"big", 1, 2},
+		{"cat", 2, 1},
+		{"the", 5, 3},
+		{"mat", 6, 1},
+	}
+	if len(toks) != len(exp) {
+		t.Fatalf("Tokenize returned %+v, expected %d tokens", toks, len(exp))
+	}
+	for i, e := range exp {
+		if tok := toks[i]; tok.Term != e.term || tok.Position != e.pos || tok.PositionIncrement != e.posIncrC {
+			t.Errorf("token %d = %+v, expected %q at %d with increment %d", i, tok, e.term, e.pos, e.posIncrC)
+		}
+	}
+}
+
+func TestTokenizerStreams(t *testing.T) {
+	text := strings.Repeat("Generalizations of naïve hopping—isn't it? ", 100)
+	opts := TokenizerOptions{Apostrophes: true, Unicode: true}
+	exp := Tokenize(text, opts)
+	tok := NewTokenizer(iotest.OneByteReader(strings.NewReader(text)), opts)
+	var i int
+	for ; tok.Scan(); i++ {
+		if i >= len(exp) || tok.Token() != exp[i] {
+			t.Fatalf("token %d = %+v, expected %+v", i, tok.Token(), exp[i])
+		}
+	}
+	if err := tok.Err(); err != nil {
+		t.Errorf("Err() = %v", err)
+	}
+	if i != len(exp) {
+		t.Errorf("read %d tokens, expected %d", i, len(exp))
+	}
+}
+
+func TestTokenizerError(t *testing.T) {
+	errTest := errors.New("test error")
+	r := io.MultiReader(strings.NewReader("some words "), iotest.ErrReader(errTest))
+	tok := NewTokenizer(r, TokenizerOptions{})
+	n := 0
+	for tok.Scan() {
+		n++
+	}
+	if n != 2 || tok.Err() != errTest {
+		t.Errorf("read %d tokens and got error %v, expected 2 tokens and %v", n, tok.Err(), errTest)
+	}
+}
@@ -0,0 +1,229 @@
+package porter
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Trace is a record of how a word was stemmed, step by step.
+type Trace struct {
+	Word  string      `json:"word"`
+	Steps []StepTrace `json:"steps"`
+	Stem  string      `json:"stem"`
+}
+
+// StepTrace records what one step of the algorithm did to a word.
+type StepTrace struct {
+	// Step is the name of the step, from "1a" to "5b".
+	Step string `json:"step"`
+	// Input is the word before the step.
+	Input string `json:"input"`
+	// Rule is the rule whose suffix matched, written as in the paper, or ""
+	// if no suffix matched.
+	Rule string `json:"rule,omitempty"`
+	// Suffix is the suffix that matched.
+	Suffix string `json:"suffix,omitempty"`
+	// Measure is m for the word less the suffix, or -1 if no suffix matched.
+	Measure int `json:"measure"`
+	// Condition is the condition of the rule that matched.
+	Condition string `json:"condition,omitempty"`
+	// Satisfied is true if the rule matched and its condition held.
+	Satisfied bool `json:"satisfied"`
+	// Output is the word after the step.
+	Output string `json:"output"`
+}
+
+// String returns the trace as one line per step.
+func (t Trace) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s -> %s\n", t.Word, t.Stem)
+	for _, step := range t.Steps {
+		buf.WriteString(step.String())
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
+
+// String returns the step as a single line.
+func (s StepTrace) String() string {
+	line := fmt.Sprintf("%-3s %s -> %s", s.Step, s.Input, s.Output)
+	if s.Rule == "" {
+		return line
+	}
+	return fmt.Sprintf("%s  [%s] m=%d, %t", line, s.Rule, s.Measure, s.Satisfied)
+}
+
+// The conditions of the rules that are not suffix rules of a Step.
+var (
+	condStep5a = Or(m1, And(MeasureEquals(1), Not(EndsCVC)))
+	condStep5b = "m>1 and *d and *L"
+)
+
+// traceSteps lists the rules of steps 1a and 1b in the order that the step
+// functions check them.  Steps 2 to 4 are traced from their Step tables.
+var traceSteps = map[string]Step{
+	"1a": {
+		{"sses", "ss", Condition{}},
+		{"ies", "i", Condition{}},
+		{"ss", "ss", Condition{}},
+		{"s", "", Condition{}},
+	},
+	"1b": {
+		{"eed", "ee", m0},
+		{"ed", "", ContainsVowel},
+		{"ing", "", ContainsVowel},
+	},
+}
+
+// ruleText writes a rule the way the paper does, e.g. "(m>0) ATIONAL -> ATE".
+func ruleText(condition, suffix, replacement string) string {
+	rule := strings.ToUpper(suffix) + " -> " + strings.ToUpper(replacement)
+	if condition != "" {
+		rule = "(" + condition + ") " + rule
+	}
+	return strings.TrimSpace(rule)
+}
+
+// traceRules fills in a step's trace from the first rule whose suffix
+// matches s.
+func (p *Porter) traceRules(step *StepTrace, rules Step, s []rune) {
+	for _, rule := range rules {
+		suffix := []rune(rule.Suffix)
+		matched := hasSuffix(s, suffix)
+		if step.Step == "1a" {
+			if p.opts.Strict {
+				matched = hasSuffixOrIs(s, suffix)
+			} else if rule.Suffix == "s" {
+				matched = len(s) > 0 && s[len(s)-1] == 's'
+			}
+		}
+		if !matched {
+			continue
+		}
+		stem := s[:len(s)-len(suffix)]
+		step.Rule = rule.String()
+		step.Suffix = rule.Suffix
+		step.Measure = int(measure(stem))
+		step.Condition = rule.Condition.String()
+		step.Satisfied = rule.Condition.Holds(stem)
+		if step.Step == "1b" && rule.Suffix != "eed" && step.Satisfied {
+			switch c := stem[len(stem)-1]; {
+			case hasSuffix(stem, []rune("at")), hasSuffix(stem, []rune("bl")), hasSuffix(stem, []rune("iz")):
+				step.Rule += "; " + ruleText("", string(stem[len(stem)-2:]), string(stem[len(stem)-2:])+"e")
+			case 'l' != c && 's' != c && 'z' != c && hasRepeatDoubleConsonantSuffix(stem):
+				step.Rule += "; (*d and not (*L or *S or *Z)) -> single letter"
+			case 1 == measure(stem) && EndsCVC.Holds(stem):
+				step.Rule += "; (m=1 and *o) -> E"
+			}
+		}
+		return
+	}
+}
+
+// trace fills in the trace of one step, before the step is applied to s.
+func (p *Porter) trace(step *StepTrace, s []rune) {
+	step.Measure = -1
+	switch step.Step {
+	case "1c":
+		if len(s) >= 2 && s[len(s)-1] == 'y' {
+			stem := s[:len(s)-1]
+			step.Rule = ruleText(ContainsVowel.String(), "y", "i")
+			step.Suffix = "y"
+			step.Measure = int(measure(stem))
+			step.Condition = ContainsVowel.String()
+			step.Satisfied = containsVowel(stem)
+		}
+	case "5a":
+		if len(s) >= 1 && s[len(s)-1] == 'e' {
+			stem := s[:len(s)-1]
+			m := measure(stem)
+			step.Rule = ruleText(condStep5a.String(), "e", "")
+			step.Suffix = "e"
+			step.Measure = int(m)
+			step.Condition = condStep5a.String()
+			step.Satisfied = condStep5a.Holds(stem)
+		}
+	case "5b":
+		if len(s) > 2 && s[len(s)-1] == 'l' && s[len(s)-2] == 'l' {
+			m := measure(s[:len(s)-1])
+			step.Rule = "(" + condStep5b + ") -> single letter"
+			step.Suffix = "l"
+			step.Measure = int(m)
+			step.Condition = condStep5b
+			step.Satisfied = m > 1
+		}
+	case "2":
+		if p.opts.Strict {
+			p.traceRules(step, porterStep2Strict, s)
+		} else {
+			p.traceRules(step, porterStep2, s)
+		}
+	case "3":
+		p.traceRules(step, porterStep3, s)
+	case "4":
+		p.traceRules(step, porterStep4, s)
+	default:
+		p.traceRules(step, traceSteps[step.Step], s)
+	}
+}
+
+// StemTrace stems a word like StemString, and returns a record of what each
+// step of the algorithm did.
+func StemTrace(word string) Trace {
+	return defaultPorter.StemTrace(word)
+}
+
+// StemTrace stems a word like StemString, and returns a record of what each
+// step of the algorithm did.  Exceptions and protected words have no steps.
+// If the stem is stemmed again, because of Options.MaxIterations, the steps
+// of each pass follow each other.
+func (p *Porter) StemTrace(word string) Trace {
+	s := []rune(word)
+	for i := 0; i < len(s); i++ {
+		s[i] = unicode.ToLower(s[i])
+	}
+	t := Trace{Word: word}
+	if stem, ok := p.lookup(s); ok {
+		t.Stem = string(stem)
+		return t
+	}
+	if len(s) == 0 || !p.opts.Strict && len(s) <= 2 {
+		t.Stem = string(s)
+		return t
+	}
+
+	step1a, step2 := Step1a, Step2
+	if p.opts.Strict {
+		step1a, step2 = Step1aStrict, Step2Strict
+	}
+	steps := []struct {
+		name string
+		fn   func([]rune) []rune
+	}{
+		{"1a", step1a},
+		{"1b", Step1b},
+		{"1c", Step1c},
+		{"2", step2},
+		{"3", Step3},
+		{"4", Step4},
+		{"5a", Step5a},
+		{"5b", Step5b},
+	}
+	for i := 0; i == 0 || i < p.opts.MaxIterations; i++ {
+		prev := string(s)
+		for _, step := range steps[:p.opts.Level.steps()] {
+			st := StepTrace{Step: step.name, Input: string(s)}
+			p.trace(&st, s)
+			s = step.fn(s)
+			st.Output = string(s)
+			t.Steps = append(t.Steps, st)
+		}
+		if string(s) == prev || len(s) == 0 || !p.opts.Strict && len(s) <= 2 {
+			break
+		}
+	}
+	t.Stem = string(s)
+	return t
+}
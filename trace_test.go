@@ -0,0 +1,135 @@
+package porter
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestStemTrace(t *testing.T) {
+	tr := StemTrace("Generalizations")
+	if tr.Stem != "gener" {
+		t.Fatalf("StemTrace(\"Generalizations\").Stem = %q, expected \"gener\"", tr.Stem)
+	}
+	exp := []struct {
+		step, output, rule string
+		measure            int
+		satisfied          bool
+	}{
+		{"1a", "generalization", "S ->", 6, true},
+		{"1b", "generalization", "", -1, false},
+		{"1c", "generalization", "", -1, false},
+		{"2", "generalize", "(m>0) IZATION -> IZE", 3, true},
+		{"3", "general", "(m>0) ALIZE -> AL", 2, true},
+		{"4", "gener", "(m>1) AL ->", 2, true},
+		{"5a", "gener", "", -1, false},
+		{"5b", "gener", "", -1, false},
+	}
+	if len(tr.Steps) != len(exp) {
+		t.Fatalf("StemTrace returned %d steps, expected %d", len(tr.Steps), len(exp))
+	}
+	for i, e := range exp {
+		st := tr.Steps[i]
+		if st.Step != e.step || st.Output != e.output || st.Rule != e.rule || st.Measure != e.measure || st.Satisfied != e.satisfied {
+			t.Errorf("step %d = %+v, expected %+v", i, st, e)
+		}
+	}
+}
+
+func TestStemTraceConditions(t *testing.T) {
+	tests := []struct {
+		word, step, rule string
+		measure          int
+		satisfied        bool
+	}{
+		{"rational", "2", "(m>0) ATIONAL -> ATE", 0, false},
+		{"hopping", "1b", "(*v*) ING ->; (*d and not (*L or *S or *Z)) -> single letter", 1, true},
+		{"filing", "1b", "(*v*) ING ->; (m=1 and *o) -> E", 1, true},
+		{"conflated", "1b", "(*v*) ED ->; AT -> ATE", 2, true},
+		{"sky", "1c", "(*v*) Y -> I", 0, false},
+		{"adoption", "4", "(m>1 and (*S or *T)) ION ->", 2, true},
+		{"rate", "5a", "(m>1 or (m=1 and not *o)) E ->", 1, false},
+		{"controll", "5b", "(m>1 and *d and *L) -> single letter", 2, true},
+	}
+	for _, test := range tests {
+		var found bool
+		for _, st := range StemTrace(test.word).Steps {
+			if st.Step != test.step {
+				continue
+			}
+			found = true
+			if st.Rule != test.rule || st.Measure != test.measure || st.Satisfied != test.satisfied {
+				t.Errorf("step %s of StemTrace(%q) = %+v, expected rule %q, m=%d, %t", test.step, test.word, st, test.rule, test.measure, test.satisfied)
+			}
+		}
+		if !found {
+			t.Errorf("StemTrace(%q) has no step %s", test.word, test.step)
+		}
+	}
+}
+
+func TestStemTraceVocabulary(t *testing.T) {
+	for _, p := range []*Porter{New(Options{}), New(Options{Strict: true}), New(Options{MaxIterations: FixpointIterations})} {
+		for _, word := range getVoc() {
+			tr := p.StemTrace(word)
+			if exp := p.StemString(word); tr.Stem != exp {
+				t.Errorf("StemTrace(%q).Stem = %q, expected %q", word, tr.Stem, exp)
+			}
+			input := strings.ToLower(word)
+			for _, st := range tr.Steps {
+				if st.Input != input {
+					t.Errorf("step %s of StemTrace(%q) has input %q, expected %q", st.Step, word, st.Input, input)
+				}
+				if !st.Satisfied && st.Output != st.Input {
+					t.Errorf("step %s of StemTrace(%q) changed %q to %q, but no rule was satisfied", st.Step, word, st.Input, st.Output)
+				}
+				input = st.Output
+			}
+		}
+	}
+}
+
+func TestStemTraceJSON(t *testing.T) {
+	tr := StemTrace("relational")
+	data, err := json.Marshal(tr)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	var got Trace
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if got.String() != tr.String() {
+		t.Errorf("JSON round trip changed trace from\n%s\nto\n%s", tr, got)
+	}
+	if st := got.Steps[3]; st.Rule != "(m>0) ATIONAL -> ATE" || st.Suffix != "ational" || st.Condition != "m>0" || st.Measure != 1 {
+		t.Errorf("JSON %s did not round trip step 2", data)
+	}
+}
+
+func TestStemTraceString(t *testing.T) {
+	exp := `relational -> relat
+1a  relational -> relational
+1b  relational -> relational
+1c  relational -> relational
+2   relational -> relate  [(m>0) ATIONAL -> ATE] m=1, true
+3   relate -> relate
+4   relate -> relate  [(m>1) ATE ->] m=1, false
+5a  relate -> relat  [(m>1 or (m=1 and not *o)) E ->] m=2, true
+5b  relat -> relat
+`
+	if s := StemTrace("relational").String(); s != exp {
+		t.Errorf("StemTrace(\"relational\").String() =\n%s\nexpected\n%s", s, exp)
+	}
+}
+
+func TestStemTraceMaxIterations(t *testing.T) {
+	// "abuse" is stemmed to "abus", then "abu", which stays the same.
+	tr := New(Options{MaxIterations: FixpointIterations}).StemTrace("abuse")
+	if tr.Stem != "abu" || len(tr.Steps) != 3*8 {
+		t.Errorf("StemTrace(%q) = %q with %d steps, expected %q with %d", "abuse", tr.Stem, len(tr.Steps), "abu", 3*8)
+	}
+	if tr.Steps[8].Input != "abus" {
+		t.Errorf("second pass of StemTrace(%q) has input %q, expected %q", "abuse", tr.Steps[8].Input, "abus")
+	}
+}
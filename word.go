@@ -0,0 +1,212 @@
+package porter
+
+import (
+	"math/bits"
+	"unicode/utf8"
+)
+
+// word is a word being stemmed, and which of its letters are consonants.
+//
+// Whether a letter is a consonant only depends on the letters before it, so
+// the classes are worked out once, when the word is made, and after that
+// only for the letters that the steps write.  The predicates of the
+// algorithm (m, *v*, *d, *o) all read the classes, rather than working them
+// out again for every letter they look at.
+type word struct {
+	s []rune
+	// cons has bit i set if s[i] is a consonant, for the first 64 letters.
+	// more has bit i%64 of more[i/64-1] set for the rest.  Only words that
+	// are longer than 64 letters allocate.
+	cons uint64
+	more []uint64
+}
+
+// newWord returns s as a word.
+func newWord(s []rune) word {
+	w := word{s: s}
+	w.classify(0)
+	return w
+}
+
+// bits returns the j'th 64 classes.
+func (w *word) bits(j int) uint64 {
+	if j == 0 {
+		return w.cons
+	}
+	return w.more[j-1]
+}
+
+// consonantLetters has bit r-'a' set if the letter r is always a consonant.
+// Y is not in it, since it depends on the letter before.
+const consonantLetters = (1<<26 - 1) &^ (1<<('a'-'a') | 1<<('e'-'a') | 1<<('i'-'a') | 1<<('o'-'a') | 1<<('u'-'a') | 1<<('y'-'a'))
+
+// classify works out the classes of the letters from s[i] to the end.
+func (w *word) classify(i int) {
+	if n := (len(w.s) - 1) / 64; n > len(w.more) {
+		// Not append, which would let the runes of the word escape to the
+		// heap.
+		more := make([]uint64, n, 2*n)
+		copy(more, w.more)
+		w.more = more
+	}
+	if i >= len(w.s) {
+		return
+	}
+	// A Y at the start of the word is a consonant, as if after a vowel.
+	var prev uint64
+	if i > 0 && w.isConsonant(i-1) {
+		prev = 1
+	}
+	block := w.bits(i / 64)
+	for ; i < len(w.s); i++ {
+		r := w.s[i]
+		consonant := uint64(1)
+		if l := uint32(r - 'a'); l < 26 {
+			consonant = consonantLetters >> l & 1
+		}
+		if r == 'y' {
+			// Y is a consonant if it follows a vowel.
+			consonant = prev ^ 1
+		}
+		prev = consonant
+		bit := uint(i % 64)
+		block = block&^(1<<bit) | consonant<<bit
+		if bit == 63 || i == len(w.s)-1 {
+			if i < 64 {
+				w.cons = block
+			} else {
+				w.more[i/64-1] = block
+			}
+			if i+1 < len(w.s) {
+				block = w.bits((i + 1) / 64)
+			}
+		}
+	}
+}
+
+// isConsonant returns true if s[i] is a consonant.
+func (w *word) isConsonant(i int) bool {
+	return w.bits(i/64)&(1<<uint(i%64)) != 0
+}
+
+// bitsOf returns the j'th 64 classes of the first k letters.  The classes
+// of the letters from k on are zero.
+func (w *word) bitsOf(j, k int) uint64 {
+	c := w.bits(j)
+	if n := k - j*64; n < 64 {
+		c &= 1<<uint(n) - 1
+	}
+	return c
+}
+
+// measure returns m for the first k letters: the number of times a vowel is
+// followed by a consonant.
+func (w *word) measure(k int) uint {
+	m := 0
+	prev := uint64(1) // before the word, as if a consonant
+	for j := 0; j*64 < k; j++ {
+		c := w.bitsOf(j, k)
+		m += bits.OnesCount64(c &^ (c<<1 | prev))
+		prev = c >> 63
+	}
+	return uint(m)
+}
+
+// containsVowel returns true if the first k letters contain a vowel.
+func (w *word) containsVowel(k int) bool {
+	for j := 0; j*64 < k; j++ {
+		v := ^w.bits(j)
+		if n := k - j*64; n < 64 {
+			v &= 1<<uint(n) - 1
+		}
+		if v != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// endsDoubleConsonant returns true if the first k letters end with a double
+// consonant.  (This is *d in the paper.)
+func (w *word) endsDoubleConsonant(k int) bool {
+	return k >= 2 && w.s[k-1] == w.s[k-2] && w.isConsonant(k-1)
+}
+
+// endsCVC returns true if the first k letters end consonant, vowel,
+// consonant.
+func (w *word) endsCVC(k int) bool {
+	return k >= 3 && w.isConsonant(k-3) && !w.isConsonant(k-2) && w.isConsonant(k-1)
+}
+
+// endsCVCNotWXY returns true if the first k letters end cvc, where the
+// second c is not W, X or Y.  (This is *o in the paper.)
+func (w *word) endsCVCNotWXY(k int) bool {
+	if !w.endsCVC(k) {
+		return false
+	}
+	c := w.s[k-1]
+	return 'w' != c && 'x' != c && 'y' != c
+}
+
+// hasSuffix checks if the word has a suffix, leaving at least one letter
+// before it.
+func (w *word) hasSuffix(suffix string) bool {
+	return len(w.s) > len(suffix) && endsWith(w.s, suffix)
+}
+
+// truncate shortens the word to its first k letters.
+func (w *word) truncate(k int) {
+	w.s = w.s[:k]
+}
+
+// replaceSuffix replaces the last n letters of the word with replacement.
+func (w *word) replaceSuffix(n int, replacement string) {
+	k := len(w.s) - n
+	if m := k + utf8.RuneCountInString(replacement); m <= cap(w.s) {
+		w.s = w.s[:m]
+	} else {
+		s := make([]rune, m)
+		copy(s, w.s)
+		w.s = s
+	}
+	i := k
+	for _, r := range replacement {
+		w.s[i] = r
+		i++
+	}
+	w.classify(k)
+}
+
+// set replaces the letter s[i].
+func (w *word) set(i int, r rune) {
+	w.s[i] = r
+	w.classify(i)
+}
+
+// isConsonant returns true if the rune represents a constanant.  Y is regarded
+// a constanant if it starts the word, or is followed by a vowel.
+func isConsonant(s []rune, i int) bool {
+	w := newWord(s[:i+1])
+	return w.isConsonant(i)
+}
+
+func measure(s []rune) uint {
+	w := newWord(s)
+	return w.measure(len(s))
+}
+
+// containsVowel returns true if the string has a vowel
+func containsVowel(s []rune) bool {
+	w := newWord(s)
+	return w.containsVowel(len(s))
+}
+
+func hasRepeatDoubleConsonantSuffix(s []rune) bool {
+	w := newWord(s)
+	return w.endsDoubleConsonant(len(s))
+}
+
+func hasCVCSuffix(s []rune) bool {
+	w := newWord(s)
+	return w.endsCVC(len(s))
+}
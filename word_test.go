@@ -0,0 +1,249 @@
+package porter
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// The predicates as they were before word, which work out the class of each
+// letter again every time they look at it.  The differential tests check
+// word against them.
+
+func refIsConsonant(s []rune, i int) bool {
+	switch s[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		if i == 0 {
+			return true
+		}
+		return !refIsConsonant(s, i-1)
+	default:
+		return true
+	}
+}
+
+func refMeasure(s []rune) uint {
+	lenS := len(s)
+	m := uint(0)
+	i := 0
+	for i = 0; i < len(s) && refIsConsonant(s, i); i++ {
+	}
+	if i == len(s) {
+		return 0
+	}
+Outer:
+	for i < len(s) {
+		for !refIsConsonant(s, i) {
+			i++
+			if i >= lenS {
+				break Outer
+			}
+		}
+		for refIsConsonant(s, i) {
+			i++
+			if i >= lenS {
+				m++
+				break Outer
+			}
+		}
+		m++
+	}
+	return m
+}
+
+func refContainsVowel(s []rune) bool {
+	for i := 0; i < len(s); i++ {
+		if !refIsConsonant(s, i) {
+			return true
+		}
+	}
+	return false
+}
+
+func refHasRepeatDoubleConsonantSuffix(s []rune) bool {
+	return len(s) >= 2 && s[len(s)-1] == s[len(s)-2] && refIsConsonant(s, len(s)-1)
+}
+
+func refHasCVCSuffix(s []rune) bool {
+	return len(s) >= 3 && refIsConsonant(s, len(s)-3) && !refIsConsonant(s, len(s)-2) && refIsConsonant(s, len(s)-1)
+}
+
+// randomLetter returns a letter, with plenty of vowels and y's, and now and
+// then a letter that is not ASCII.
+func randomLetter(rnd *rand.Rand) rune {
+	const letters = "aeiouyyyybcdlmnstwxz"
+	if rnd.Intn(50) == 0 {
+		return 'é'
+	}
+	return rune(letters[rnd.Intn(len(letters))])
+}
+
+// randomLetters returns a word of up to max random letters.
+func randomLetters(rnd *rand.Rand, max int) []rune {
+	s := make([]rune, rnd.Intn(max+1))
+	for i := range s {
+		s[i] = randomLetter(rnd)
+	}
+	return s
+}
+
+// checkWord compares every predicate of w, for every prefix, with the
+// reference predicates.
+func checkWord(t *testing.T, w *word, what string) {
+	for k := 0; k <= len(w.s); k++ {
+		s := w.s[:k]
+		if k > 0 && w.isConsonant(k-1) != refIsConsonant(s, k-1) {
+			t.Errorf("%s: isConsonant(%q, %d) = %t", what, string(w.s), k-1, w.isConsonant(k-1))
+		}
+		if m, exp := w.measure(k), refMeasure(s); m != exp {
+			t.Errorf("%s: measure(%q) = %d, expected %d", what, string(s), m, exp)
+		}
+		if v, exp := w.containsVowel(k), refContainsVowel(s); v != exp {
+			t.Errorf("%s: containsVowel(%q) = %t, expected %t", what, string(s), v, exp)
+		}
+		if d, exp := w.endsDoubleConsonant(k), refHasRepeatDoubleConsonantSuffix(s); d != exp {
+			t.Errorf("%s: endsDoubleConsonant(%q) = %t, expected %t", what, string(s), d, exp)
+		}
+		if o, exp := w.endsCVC(k), refHasCVCSuffix(s); o != exp {
+			t.Errorf("%s: endsCVC(%q) = %t, expected %t", what, string(s), o, exp)
+		}
+	}
+}
+
+// TestWordDifferential checks word against the reference predicates, for
+// random words, and after random changes like the steps make.
+func TestWordDifferential(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for n := 0; n < 3000; n++ {
+		s := randomLetters(rnd, 150)
+		w := newWord(s)
+		checkWord(t, &w, "newWord")
+		if t.Failed() {
+			return
+		}
+		for c := 0; c < 5 && len(w.s) > 0; c++ {
+			switch rnd.Intn(3) {
+			case 0:
+				w.truncate(rnd.Intn(len(w.s) + 1))
+				checkWord(t, &w, "truncate")
+			case 1:
+				suffix := string(randomLetters(rnd, 4))
+				w.replaceSuffix(rnd.Intn(len(w.s)+1), suffix)
+				checkWord(t, &w, "replaceSuffix")
+			case 2:
+				w.set(rnd.Intn(len(w.s)), randomLetter(rnd))
+				checkWord(t, &w, "set")
+			}
+			if t.Failed() {
+				return
+			}
+		}
+	}
+}
+
+// TestWordVocabulary checks the predicates of word against the reference
+// predicates on every word of the vocabulary.
+func TestWordVocabulary(t *testing.T) {
+	for _, v := range getVoc() {
+		w := newWord([]rune(v))
+		checkWord(t, &w, "vocabulary")
+		if t.Failed() {
+			return
+		}
+	}
+}
+
+func BenchmarkMeasure(b *testing.B) {
+	ss := getVoc()
+	rs := make([][]rune, len(ss))
+	for i, s := range ss {
+		rs[i] = []rune(s)
+	}
+	b.Run("reference", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, s := range rs {
+				refMeasure(s)
+			}
+		}
+	})
+	b.Run("word", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, s := range rs {
+				w := newWord(s)
+				w.measure(len(s))
+			}
+		}
+	})
+}
+
+func BenchmarkNewWord(b *testing.B) {
+	ss := getVoc()
+	rs := make([][]rune, len(ss))
+	for i, s := range ss {
+		rs[i] = []rune(s)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, s := range rs {
+			w := newWord(s)
+			_ = w
+		}
+	}
+}
+
+// BenchmarkWordStep benchmarks each step on words whose letters have
+// already been classified, as they are when a word is stemmed.  The words
+// are put back as they were before each pass over the vocabulary.
+func BenchmarkWordStep(b *testing.B) {
+	var runes []rune
+	var starts []int
+	for _, s := range getVoc() {
+		starts = append(starts, len(runes))
+		runes = append(runes, []rune(s)...)
+	}
+	starts = append(starts, len(runes))
+	// words returns the words of the vocabulary, in buf.
+	words := func(buf []rune) []word {
+		words := make([]word, len(starts)-1)
+		for i := range words {
+			words[i] = newWord(buf[starts[i]:starts[i+1]:starts[i+1]])
+		}
+		return words
+	}
+	steps := []struct {
+		name string
+		fn   func(w *word)
+	}{
+		{"1a", (*word).step1a},
+		{"1b", (*word).step1b},
+		{"1c", (*word).step1c},
+		{"2", func(w *word) { w.step2(true) }},
+		{"3", (*word).step3},
+		{"4", (*word).step4},
+		{"5a", (*word).step5a},
+		{"5b", (*word).step5b},
+	}
+	for _, step := range steps {
+		b.Run(step.name, func(b *testing.B) {
+			buf := make([]rune, len(runes))
+			copy(buf, runes)
+			classified := words(buf)
+			scratch := make([]word, len(classified))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				copy(buf, runes)
+				copy(scratch, classified)
+				b.StartTimer()
+				for j := range scratch {
+					step.fn(&scratch[j])
+				}
+			}
+		})
+	}
+}